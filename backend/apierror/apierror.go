@@ -0,0 +1,157 @@
+// Package apierror classifies failures so handlers can answer every error
+// with the same JSON envelope and a status that reflects whose fault it was.
+//
+// Errors carry a Kind from the point they are created, usually an upstream
+// client or an endpoint wrapper, and keep it as they are wrapped on the way
+// up. Kinds are themselves errors, so callers test them with errors.Is:
+//
+//	if errors.Is(err, apierror.NotFound) { ... }
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Kind is the category of a failure.
+type Kind int
+
+const (
+	Internal       Kind = iota // A bug or failure on our side
+	Validation                 // The request's parameters are invalid
+	NotFound                   // The requested resource does not exist
+	Unavailable                // The upstream is down, timing out or rate limiting us
+	Blocked                    // The upstream refused us, e.g. with an HTML error page
+	Decode                     // The upstream's response did not have the expected shape
+	QuotaExhausted             // Our request budget for the upstream is spent
+	Unauthorized               // The caller did not present valid credentials
+	Conflict                   // The request clashes with work already in progress
+	Canceled                   // The caller went away before the request finished
+)
+
+// StatusClientClosedRequest is the non-standard status, borrowed from nginx,
+// recorded for requests the caller abandoned. The caller never sees it.
+const StatusClientClosedRequest = 499
+
+var kindInfo = map[Kind]struct {
+	code    string
+	status  int
+	message string // Shown instead of the error's own text, which may include upstream URLs
+}{
+	Internal:       {"internal_error", http.StatusInternalServerError, "internal server error"},
+	Validation:     {"invalid_request", http.StatusBadRequest, ""},
+	NotFound:       {"not_found", http.StatusNotFound, ""},
+	Unavailable:    {"upstream_unavailable", http.StatusServiceUnavailable, "upstream is unavailable, try again later"},
+	Blocked:        {"upstream_blocked", http.StatusBadGateway, "upstream refused the request"},
+	Decode:         {"upstream_bad_response", http.StatusBadGateway, "upstream returned an unexpected response"},
+	QuotaExhausted: {"quota_exhausted", http.StatusServiceUnavailable, "upstream request budget is exhausted, try again later"},
+	Unauthorized:   {"unauthorized", http.StatusUnauthorized, ""},
+	Conflict:       {"conflict", http.StatusConflict, ""},
+	Canceled:       {"request_canceled", StatusClientClosedRequest, "request canceled by the client"},
+}
+
+// Code is the machine-readable name of the kind used in error envelopes.
+func (k Kind) Code() string {
+	if info, ok := kindInfo[k]; ok {
+		return info.code
+	}
+	return kindInfo[Internal].code
+}
+
+// Status is the HTTP status a failure of this kind is answered with.
+func (k Kind) Status() int {
+	if info, ok := kindInfo[k]; ok {
+		return info.status
+	}
+	return http.StatusInternalServerError
+}
+
+// Error makes a Kind usable as an errors.Is target.
+func (k Kind) Error() string {
+	return k.Code()
+}
+
+// Error is a failure tagged with its Kind.
+type Error struct {
+	kind Kind
+	err  error
+}
+
+// New returns an error of kind with a message formatted as fmt.Errorf does, %w included.
+func New(kind Kind, format string, args ...any) error {
+	return &Error{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// Wrap tags err with kind, replacing any kind it already had. A nil err stays nil.
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{kind: kind, err: err}
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Kind returns the error's category.
+func (e *Error) Kind() Kind {
+	return e.kind
+}
+
+// Is matches the error's Kind.
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(Kind)
+	return ok && kind == e.kind
+}
+
+// KindOf returns the kind of the outermost classified error in err's chain.
+// Cancellation is Canceled wherever it appears in the chain, since it means the
+// caller disconnected rather than that anything failed. Deadlines are
+// Unavailable, since they mean an upstream was too slow, and anything
+// unclassified is Internal.
+func KindOf(err error) Kind {
+	if errors.Is(err, context.Canceled) {
+		return Canceled
+	}
+	var classified interface{ Kind() Kind }
+	if errors.As(err, &classified) {
+		return classified.Kind()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Unavailable
+	}
+	return Internal
+}
+
+// Envelope is the JSON body of every error response.
+type Envelope struct {
+	Error Body `json:"error"`
+}
+
+// Body describes a single error.
+type Body struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Response returns the status and envelope err is answered with. Client
+// errors report their own message; the rest report a generic one so upstream
+// URLs and credentials never reach the response.
+func Response(err error) (int, Envelope) {
+	kind := KindOf(err)
+	if _, ok := kindInfo[kind]; !ok {
+		kind = Internal
+	}
+	message := kindInfo[kind].message
+	if message == "" {
+		message = err.Error()
+	}
+	return kind.Status(), Envelope{Error: Body{Code: kind.Code(), Message: message}}
+}
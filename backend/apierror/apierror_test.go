@@ -0,0 +1,76 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestKindSurvivesWrapping(t *testing.T) {
+	err := fmt.Errorf("fetching players: %w", New(NotFound, "player %d not found", 2544))
+
+	if !errors.Is(err, NotFound) || errors.Is(err, Validation) {
+		t.Errorf("errors.Is on %v does not match only NotFound", err)
+	}
+	if kind := KindOf(err); kind != NotFound {
+		t.Errorf("KindOf = %s, want not_found", kind.Code())
+	}
+	if err.Error() != "fetching players: player 2544 not found" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	// The outermost kind wins over the one it wraps
+	if kind := KindOf(Wrap(Decode, err)); kind != Decode {
+		t.Errorf("KindOf re-wrapped error = %s, want upstream_bad_response", kind.Code())
+	}
+	if Wrap(Decode, nil) != nil {
+		t.Error("Wrap(nil) is not nil")
+	}
+}
+
+func TestKindOfUnclassified(t *testing.T) {
+	if kind := KindOf(errors.New("boom")); kind != Internal {
+		t.Errorf("KindOf plain error = %s, want internal_error", kind.Code())
+	}
+	if kind := KindOf(fmt.Errorf("waiting: %w", context.DeadlineExceeded)); kind != Unavailable {
+		t.Errorf("KindOf deadline = %s, want upstream_unavailable", kind.Code())
+	}
+	// A disconnected caller is not a server failure, even inside an upstream error
+	if kind := KindOf(New(Unavailable, "error making request: %w", context.Canceled)); kind != Canceled {
+		t.Errorf("KindOf cancellation = %s, want request_canceled", kind.Code())
+	}
+	if status := Canceled.Status(); status >= 500 {
+		t.Errorf("Canceled.Status() = %d, want a non-5xx status", status)
+	}
+}
+
+func TestResponse(t *testing.T) {
+	tests := []struct {
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{New(Validation, "invalid Season format"), http.StatusBadRequest, "invalid_request", "invalid Season format"},
+		{New(NotFound, "no game logs"), http.StatusNotFound, "not_found", "no game logs"},
+		{New(Unavailable, "GET https://api.example.com/?apiKey=secret: connection refused"), http.StatusServiceUnavailable, "upstream_unavailable", "upstream is unavailable, try again later"},
+		{New(Blocked, "NBA API returned HTML error page"), http.StatusBadGateway, "upstream_blocked", "upstream refused the request"},
+		{New(Decode, "failed to parse JSON"), http.StatusBadGateway, "upstream_bad_response", "upstream returned an unexpected response"},
+		{New(QuotaExhausted, "budget exceeded"), http.StatusServiceUnavailable, "quota_exhausted", "upstream request budget is exhausted, try again later"},
+		{New(Unauthorized, "admin token required"), http.StatusUnauthorized, "unauthorized", "admin token required"},
+		{New(Conflict, "warm set nba-players is already running"), http.StatusConflict, "conflict", "warm set nba-players is already running"},
+		{errors.New("nil map"), http.StatusInternalServerError, "internal_error", "internal server error"},
+	}
+	for _, test := range tests {
+		status, envelope := Response(test.err)
+		if status != test.status || envelope.Error.Code != test.code || envelope.Error.Message != test.message {
+			t.Errorf("Response(%v) = %d %+v, want %d %s %q", test.err, status, envelope.Error, test.status, test.code, test.message)
+		}
+		if strings.Contains(envelope.Error.Message, "secret") {
+			t.Errorf("Response(%v) leaks the upstream URL", test.err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package background runs work that outlives the request that started it,
+// such as stale-while-revalidate cache refreshes and cache warm-up runs, so
+// shutdown can let it finish and cancel whatever is left.
+package background
+
+import (
+	"context"
+	"sync"
+)
+
+// Group tracks running background work.
+type Group struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	stop    context.Context
+	cancel  context.CancelFunc
+	closing bool
+}
+
+// NewGroup creates a Group ready to run work.
+func NewGroup() *Group {
+	stop, cancel := context.WithCancel(context.Background())
+	return &Group{stop: stop, cancel: cancel}
+}
+
+// Default is the Group shutdown drains.
+var Default = NewGroup()
+
+// Go runs fn in a new goroutine. Its context keeps parent's values, such as
+// the request's logger and trace, but not its cancellation or deadline; it is
+// cancelled only if Shutdown gives up waiting. Go reports false, without
+// running fn, once Shutdown has started.
+func (g *Group) Go(parent context.Context, fn func(ctx context.Context)) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closing {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stopCancel := context.AfterFunc(g.stop, cancel)
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer stopCancel()
+		defer cancel()
+		fn(ctx)
+	}()
+	return true
+}
+
+// Shutdown refuses new work and waits for running work to return. When ctx
+// is done first, the running work is cancelled and waited for, and ctx's
+// error is returned.
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.closing = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		g.cancel()
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Go runs fn on Default.
+func Go(parent context.Context, fn func(ctx context.Context)) bool {
+	return Default.Go(parent, fn)
+}
+
+// Shutdown drains Default.
+func Shutdown(ctx context.Context) error {
+	return Default.Shutdown(ctx)
+}
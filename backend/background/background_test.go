@@ -0,0 +1,74 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+func TestShutdownWaitsForRunningWork(t *testing.T) {
+	g := NewGroup()
+	parent, cancelParent := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "request"))
+	release := make(chan struct{})
+	finished := make(chan error, 1)
+	if !g.Go(parent, func(ctx context.Context) {
+		if ctx.Value(ctxKey{}) != "request" {
+			finished <- errors.New("work lost the parent's values")
+			return
+		}
+		<-release
+		finished <- ctx.Err()
+	}) {
+		t.Fatal("Go refused work before Shutdown")
+	}
+	cancelParent() // The request finishing must not cancel its background work
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- g.Shutdown(context.Background()) }()
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned %v before the work finished", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-finished; err != nil {
+		t.Errorf("work finished with %v, want nil", err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown returned %v, want nil", err)
+	}
+}
+
+func TestShutdownCancelsWorkPastDeadline(t *testing.T) {
+	g := NewGroup()
+	cancelled := make(chan struct{})
+	g.Go(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown returned %v, want context.DeadlineExceeded", err)
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Error("Shutdown returned before the cancelled work did")
+	}
+}
+
+func TestGoRefusesWorkAfterShutdown(t *testing.T) {
+	g := NewGroup()
+	if err := g.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned %v", err)
+	}
+	if g.Go(context.Background(), func(context.Context) { t.Error("work ran after Shutdown") }) {
+		t.Error("Go accepted work after Shutdown")
+	}
+}
@@ -0,0 +1,46 @@
+// Command fakenba serves a fake stats.nba.com so the API can run without
+// access to the real one:
+//
+//	go run ./cmd/fakenba -addr :8081
+//	NBA_BASE_URL=http://localhost:8081/stats/ go run .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"sports_api/fakenba"
+	"strings"
+)
+
+// faultFlags collects repeated -fault endpoint=fault flags.
+type faultFlags []string
+
+func (f *faultFlags) String() string     { return strings.Join(*f, ",") }
+func (f *faultFlags) Set(v string) error { *f = append(*f, v); return nil }
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	encoding := flag.String("encoding", "", "force gzip, deflate or identity responses; empty follows Accept-Encoding")
+	var faults faultFlags
+	flag.Var(&faults, "fault", "inject a fault as endpoint=html|429|500|timeout, or just the fault for every endpoint; repeatable")
+	flag.Parse()
+
+	handler := fakenba.NewHandler()
+	handler.SetEncoding(*encoding)
+	for _, value := range faults {
+		endpoint, name, ok := strings.Cut(value, "=")
+		if !ok {
+			endpoint, name = "", value
+		}
+		fault, err := fakenba.ParseFault(name)
+		if err != nil {
+			log.Fatalf("Invalid -fault %q: %v", value, err)
+		}
+		handler.SetFault(endpoint, fault, 0)
+	}
+
+	fmt.Printf("Fake stats.nba.com listening on %s; set NBA_BASE_URL=http://localhost%s%s\n", *addr, *addr, fakenba.Prefix)
+	log.Fatal(http.ListenAndServe(*addr, handler))
+}
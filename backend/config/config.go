@@ -0,0 +1,450 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting that differs between environments.
+type Config struct {
+	Server     Server               `yaml:"server"`
+	Log        Log                  `yaml:"log"`
+	Tracing    Tracing              `yaml:"tracing"`
+	Admin      Admin                `yaml:"admin"`
+	Cache      Cache                `yaml:"cache"`
+	NBA        Upstream             `yaml:"nba"`
+	MLB        Upstream             `yaml:"mlb"`
+	NHL        NHL                  `yaml:"nhl"`
+	Odds       Odds                 `yaml:"odds"`
+	Seasons    Seasons              `yaml:"seasons"`
+	RateLimits map[string]RateLimit `yaml:"rate_limits"` // Keyed by upstream host
+}
+
+// Server configures the HTTP listener and router.
+type Server struct {
+	Addr        string   `yaml:"addr"`
+	CORSOrigins []string `yaml:"cors_origins"`
+	// Timeouts applied to each connection; zero disables the limit.
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout bounds how long in-flight requests and background work
+	// may run after a shutdown signal before they are cancelled.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// Log configures the structured logger.
+type Log struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error
+	Format string `yaml:"format"` // text or json
+}
+
+// Tracing configures OpenTelemetry trace export.
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`     // none, stdout or otlp
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/HTTP collector URL; empty defers to OTEL_EXPORTER_OTLP_ENDPOINT
+	SampleRatio float64 `yaml:"sample_ratio"` // Fraction of new traces recorded, from 0 to 1
+	ServiceName string  `yaml:"service_name"`
+}
+
+// Admin configures the cache administration routes.
+type Admin struct {
+	Token string `yaml:"token"` // Bearer token; empty disables the admin routes
+}
+
+// Cache selects and configures the response cache backend.
+type Cache struct {
+	Backend      string                   `yaml:"backend"` // memory, redis or file
+	RedisAddr    string                   `yaml:"redis_addr"`
+	Dir          string                   `yaml:"dir"`
+	MaxEntries   int                      `yaml:"max_entries"`
+	TTLOverrides map[string]time.Duration `yaml:"ttl_overrides"` // Endpoint pattern to TTL
+}
+
+// Upstream configures a provider's session client.
+type Upstream struct {
+	BaseURL string        `yaml:"base_url"`
+	Timeout time.Duration `yaml:"timeout"`
+	Proxy   string        `yaml:"proxy"`
+}
+
+// NHL configures the NHL clients. The stats REST API and the web API live on
+// separate hosts but share the timeout and proxy settings.
+type NHL struct {
+	Upstream   `yaml:",inline"` // BaseURL is the stats REST API
+	WebBaseURL string           `yaml:"web_base_url"`
+}
+
+// Odds configures The Odds API client.
+type Odds struct {
+	Upstream      `yaml:",inline"`
+	APIKey        string   `yaml:"api_key"`
+	APIKeys       []string `yaml:"api_keys"`       // Further keys the client rotates across
+	APIKeysFile   string   `yaml:"api_keys_file"`  // Secrets file with one key per line, read into APIKeys
+	DailyBudget   int      `yaml:"daily_budget"`   // Request credits per UTC day for non-critical fetches; zero is unlimited
+	MonthlyBudget int      `yaml:"monthly_budget"` // Request credits per month for non-critical fetches; zero is unlimited
+}
+
+// Keys returns APIKey followed by APIKeys, without blanks or duplicates.
+func (o Odds) Keys() []string {
+	var keys []string
+	for _, key := range append([]string{o.APIKey}, o.APIKeys...) {
+		if key = strings.TrimSpace(key); key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Seasons are the current seasons in each provider's format.
+type Seasons struct {
+	NBA  string `yaml:"nba"`  // e.g. 2024-25
+	WNBA string `yaml:"wnba"` // e.g. 2024-25
+	NHL  string `yaml:"nhl"`  // e.g. 20242025
+}
+
+// RateLimit bounds requests to a single upstream host.
+type RateLimit struct {
+	Rate        float64 `yaml:"rate"`
+	Burst       int     `yaml:"burst"`
+	MaxInFlight int     `yaml:"max_in_flight"`
+}
+
+// Default returns the settings the API ran with before it was configurable.
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Addr:              ":8080",
+			CORSOrigins:       []string{"*"},
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      2 * time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Log: Log{Level: "info", Format: "text"},
+		Tracing: Tracing{
+			Exporter:    "none",
+			SampleRatio: 1,
+			ServiceName: "sports_api",
+		},
+		Cache: Cache{
+			Backend:    "redis",
+			RedisAddr:  "localhost:6379",
+			Dir:        "results",
+			MaxEntries: 10000,
+		},
+		NBA: Upstream{BaseURL: "https://stats.nba.com/stats/", Timeout: 20 * time.Second},
+		MLB: Upstream{BaseURL: "https://statsapi.mlb.com", Timeout: 20 * time.Second},
+		NHL: NHL{
+			Upstream:   Upstream{BaseURL: "https://api.nhle.com/stats/rest/", Timeout: 20 * time.Second},
+			WebBaseURL: "https://api-web.nhle.com/v1/",
+		},
+		Odds: Odds{Upstream: Upstream{BaseURL: "https://api.the-odds-api.com/v4/sports", Timeout: 20 * time.Second}},
+		Seasons: Seasons{
+			NBA:  "2024-25",
+			WNBA: "2024-25",
+			NHL:  "20242025",
+		},
+	}
+}
+
+// Load reads the YAML file at path over the defaults, applies environment
+// overrides and validates the result. An empty path skips the file.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if cfg.Odds.APIKeysFile != "" {
+		keys, err := readKeysFile(cfg.Odds.APIKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading odds.api_keys_file: %w", err)
+		}
+		cfg.Odds.APIKeys = append(cfg.Odds.APIKeys, keys...)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides settings from environment variables looked up with lookup.
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	vars := map[string]*string{
+		"SERVER_ADDR":        &c.Server.Addr,
+		"LOG_LEVEL":          &c.Log.Level,
+		"LOG_FORMAT":         &c.Log.Format,
+		"TRACING_EXPORTER":   &c.Tracing.Exporter,
+		"TRACING_ENDPOINT":   &c.Tracing.Endpoint,
+		"OTEL_SERVICE_NAME":  &c.Tracing.ServiceName,
+		"ADMIN_TOKEN":        &c.Admin.Token,
+		"CACHE_BACKEND":      &c.Cache.Backend,
+		"REDIS_ADDR":         &c.Cache.RedisAddr,
+		"CACHE_DIR":          &c.Cache.Dir,
+		"NBA_BASE_URL":       &c.NBA.BaseURL,
+		"MLB_BASE_URL":       &c.MLB.BaseURL,
+		"NHL_BASE_URL":       &c.NHL.BaseURL,
+		"NHL_WEB_BASE_URL":   &c.NHL.WebBaseURL,
+		"ODDS_BASE_URL":      &c.Odds.BaseURL,
+		"ODDS_API_KEY":       &c.Odds.APIKey,
+		"ODDS_API_KEYS_FILE": &c.Odds.APIKeysFile,
+		"NBA_SEASON":         &c.Seasons.NBA,
+		"WNBA_SEASON":        &c.Seasons.WNBA,
+		"NHL_SEASON":         &c.Seasons.NHL,
+		"NBA_PROXY":          &c.NBA.Proxy,
+		"MLB_PROXY":          &c.MLB.Proxy,
+		"NHL_PROXY":          &c.NHL.Proxy,
+		"ODDS_PROXY":         &c.Odds.Proxy,
+	}
+	for name, field := range vars {
+		if value, ok := lookup(name); ok {
+			*field = value
+		}
+	}
+
+	if port, ok := lookup("PORT"); ok {
+		c.Server.Addr = ":" + port
+	}
+	if origins, ok := lookup("CORS_ORIGINS"); ok {
+		c.Server.CORSOrigins = splitList(origins)
+	}
+	if keys, ok := lookup("ODDS_API_KEYS"); ok {
+		c.Odds.APIKeys = splitList(keys)
+	}
+	intVars := map[string]*int{
+		"CACHE_MAX_ENTRIES":   &c.Cache.MaxEntries,
+		"ODDS_DAILY_BUDGET":   &c.Odds.DailyBudget,
+		"ODDS_MONTHLY_BUDGET": &c.Odds.MonthlyBudget,
+	}
+	for name, field := range intVars {
+		if value, ok := lookup(name); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", name, value, err)
+			}
+			*field = n
+		}
+	}
+	durationVars := map[string]*time.Duration{
+		"SERVER_READ_HEADER_TIMEOUT": &c.Server.ReadHeaderTimeout,
+		"SERVER_READ_TIMEOUT":        &c.Server.ReadTimeout,
+		"SERVER_WRITE_TIMEOUT":       &c.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        &c.Server.IdleTimeout,
+		"SERVER_SHUTDOWN_TIMEOUT":    &c.Server.ShutdownTimeout,
+	}
+	for name, field := range durationVars {
+		if value, ok := lookup(name); ok {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", name, value, err)
+			}
+			*field = d
+		}
+	}
+	if value, ok := lookup("TRACING_SAMPLE_RATIO"); ok {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid TRACING_SAMPLE_RATIO %q: %w", value, err)
+		}
+		c.Tracing.SampleRatio = ratio
+	}
+	if value, ok := lookup("CACHE_TTL_OVERRIDES"); ok {
+		overrides, err := ParseTTLOverrides(value)
+		if err != nil {
+			return fmt.Errorf("invalid CACHE_TTL_OVERRIDES: %w", err)
+		}
+		if c.Cache.TTLOverrides == nil {
+			c.Cache.TTLOverrides = make(map[string]time.Duration)
+		}
+		for pattern, ttl := range overrides {
+			c.Cache.TTLOverrides[pattern] = ttl
+		}
+	}
+	return nil
+}
+
+var (
+	nbaSeason = regexp.MustCompile(`^\d{4}-\d{2}$`)
+	nhlSeason = regexp.MustCompile(`^\d{8}$`)
+)
+
+// Validate reports every invalid setting at once so startup fails with the full picture.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr is required"))
+	}
+	if len(c.Server.CORSOrigins) == 0 {
+		errs = append(errs, errors.New("server.cors_origins must list at least one origin"))
+	}
+	if c.Server.ReadHeaderTimeout < 0 || c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		errs = append(errs, errors.New("server timeouts must not be negative"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout must be positive"))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("log.level %q must be debug, info, warn or error", c.Log.Level))
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		errs = append(errs, fmt.Errorf("log.format %q must be text or json", c.Log.Format))
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Tracing.Endpoint != "" {
+			if u, err := url.Parse(c.Tracing.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+				errs = append(errs, fmt.Errorf("tracing.endpoint %q must be an absolute URL", c.Tracing.Endpoint))
+			}
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter %q must be none, stdout or otlp", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio %v must be between 0 and 1", c.Tracing.SampleRatio))
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, errors.New("tracing.service_name is required"))
+	}
+
+	switch c.Cache.Backend {
+	case "memory":
+	case "redis":
+		if c.Cache.RedisAddr == "" {
+			errs = append(errs, errors.New("cache.redis_addr is required for the redis backend"))
+		}
+	case "file":
+		if c.Cache.Dir == "" {
+			errs = append(errs, errors.New("cache.dir is required for the file backend"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("cache.backend %q must be memory, redis or file", c.Cache.Backend))
+	}
+	if c.Cache.MaxEntries < 0 {
+		errs = append(errs, errors.New("cache.max_entries must not be negative"))
+	}
+	if c.Odds.DailyBudget < 0 || c.Odds.MonthlyBudget < 0 {
+		errs = append(errs, errors.New("odds budgets must not be negative"))
+	}
+
+	upstreams := []struct {
+		name string
+		Upstream
+	}{{"nba", c.NBA}, {"mlb", c.MLB}, {"nhl", c.NHL.Upstream}, {"odds", c.Odds.Upstream}}
+	for _, upstream := range upstreams {
+		name := upstream.name
+		if u, err := url.Parse(upstream.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s.base_url %q must be an absolute URL", name, upstream.BaseURL))
+		}
+		if upstream.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("%s.timeout must not be negative", name))
+		}
+		if upstream.Proxy != "" {
+			if u, err := url.Parse(upstream.Proxy); err != nil || u.Host == "" {
+				errs = append(errs, fmt.Errorf("%s.proxy %q must be a URL", name, upstream.Proxy))
+			}
+		}
+	}
+
+	if u, err := url.Parse(c.NHL.WebBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("nhl.web_base_url %q must be an absolute URL", c.NHL.WebBaseURL))
+	}
+
+	if !nbaSeason.MatchString(c.Seasons.NBA) {
+		errs = append(errs, fmt.Errorf("seasons.nba %q must be formatted YYYY-YY", c.Seasons.NBA))
+	}
+	if !nbaSeason.MatchString(c.Seasons.WNBA) {
+		errs = append(errs, fmt.Errorf("seasons.wnba %q must be formatted YYYY-YY", c.Seasons.WNBA))
+	}
+	if !nhlSeason.MatchString(c.Seasons.NHL) {
+		errs = append(errs, fmt.Errorf("seasons.nhl %q must be formatted YYYYYYYY", c.Seasons.NHL))
+	}
+
+	for host, limit := range c.RateLimits {
+		if limit.Rate < 0 || limit.Burst < 0 || limit.MaxInFlight < 0 {
+			errs = append(errs, fmt.Errorf("rate_limits.%s must not be negative", host))
+		}
+	}
+	for pattern, ttl := range c.Cache.TTLOverrides {
+		if ttl < 0 {
+			errs = append(errs, fmt.Errorf("cache.ttl_overrides.%s must not be negative", pattern))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ParseTTLOverrides parses comma separated pattern=duration pairs,
+// e.g. "scoreboardv2=10s,/*/events/*/odds=2m".
+func ParseTTLOverrides(value string) (map[string]time.Duration, error) {
+	overrides := make(map[string]time.Duration)
+	for _, pair := range splitList(value) {
+		pattern, duration, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: want pattern=duration", pair)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pair, err)
+		}
+		overrides[strings.TrimSpace(pattern)] = ttl
+	}
+	return overrides, nil
+}
+
+// readKeysFile reads one key per line from path, skipping blank lines and # comments.
+func readKeysFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
+	}
+	return keys, nil
+}
+
+// splitList splits a comma separated list, dropping blank entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
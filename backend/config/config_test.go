@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadFileWithEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+server:
+  addr: ":9090"
+  cors_origins: ["https://statspro.example.com"]
+cache:
+  backend: memory
+  ttl_overrides:
+    scoreboardv2: 10s
+nba:
+  base_url: https://staging.example.com/stats/
+  timeout: 5s
+seasons:
+  nba: 2025-26
+rate_limits:
+  stats.nba.com:
+    rate: 1
+    burst: 2
+    max_in_flight: 2
+`), 0o644)
+	if err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+	keysFile := filepath.Join(t.TempDir(), "odds-keys")
+	if err := os.WriteFile(keysFile, []byte("# team keys\nfrom-file\n\nfrom-env\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+	t.Setenv("ODDS_API_KEY", "from-env")
+	t.Setenv("ODDS_API_KEYS", "from-list, ")
+	t.Setenv("ODDS_API_KEYS_FILE", keysFile)
+	t.Setenv("PORT", "7070")
+	t.Setenv("CACHE_TTL_OVERRIDES", "drafthistory=720h")
+	t.Setenv("SERVER_SHUTDOWN_TIMEOUT", "45s")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Server.Addr != ":7070" {
+		t.Errorf("Server.Addr = %q, want PORT override :7070", cfg.Server.Addr)
+	}
+	if cfg.Server.ShutdownTimeout != 45*time.Second || cfg.Server.WriteTimeout != Default().Server.WriteTimeout {
+		t.Errorf("Server = %+v, want shutdown timeout from env and default write timeout", cfg.Server)
+	}
+	if cfg.NBA.BaseURL != "https://staging.example.com/stats/" || cfg.NBA.Timeout != 5*time.Second {
+		t.Errorf("NBA = %+v, want file settings", cfg.NBA)
+	}
+	if cfg.MLB.BaseURL != Default().MLB.BaseURL {
+		t.Errorf("MLB.BaseURL = %q, want default", cfg.MLB.BaseURL)
+	}
+	if cfg.Seasons.NBA != "2025-26" || cfg.Seasons.NHL != "20242025" {
+		t.Errorf("Seasons = %+v, want NBA from file and NHL default", cfg.Seasons)
+	}
+	if cfg.Odds.APIKey != "from-env" {
+		t.Errorf("Odds.APIKey = %q, want from-env", cfg.Odds.APIKey)
+	}
+	if keys := strings.Join(cfg.Odds.Keys(), ","); keys != "from-env,from-list,from-file" {
+		t.Errorf("Odds.Keys() = %s, want the env key, list and file without duplicates", keys)
+	}
+	if cfg.Cache.TTLOverrides["scoreboardv2"] != 10*time.Second || cfg.Cache.TTLOverrides["drafthistory"] != 720*time.Hour {
+		t.Errorf("Cache.TTLOverrides = %v, want file and env overrides merged", cfg.Cache.TTLOverrides)
+	}
+	if cfg.RateLimits["stats.nba.com"].MaxInFlight != 2 {
+		t.Errorf("RateLimits = %v, want stats.nba.com limit from file", cfg.RateLimits)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		want string
+	}{
+		{"unknown field", "serverr:\n  addr: :8080\n", nil, "field serverr not found"},
+		{"unknown backend", "cache:\n  backend: memcached\n", nil, "cache.backend"},
+		{"relative base URL", "nhl:\n  base_url: api.nhle.com\n", nil, "nhl.base_url"},
+		{"bad season", "", map[string]string{"NHL_SEASON": "2024-25"}, "seasons.nhl"},
+		{"bad env number", "", map[string]string{"CACHE_MAX_ENTRIES": "lots"}, "CACHE_MAX_ENTRIES"},
+		{"bad log level", "log:\n  level: verbose\n", nil, "log.level"},
+		{"bad log format", "", map[string]string{"LOG_FORMAT": "xml"}, "log.format"},
+		{"unknown exporter", "tracing:\n  exporter: jaeger\n", nil, "tracing.exporter"},
+		{"bad sample ratio", "", map[string]string{"TRACING_SAMPLE_RATIO": "1.5"}, "tracing.sample_ratio"},
+		{"bad env duration", "", map[string]string{"SERVER_WRITE_TIMEOUT": "2 minutes"}, "SERVER_WRITE_TIMEOUT"},
+		{"missing keys file", "", map[string]string{"ODDS_API_KEYS_FILE": "/nonexistent/keys"}, "odds.api_keys_file"},
+		{"no shutdown timeout", "server:\n  shutdown_timeout: 0s\n", nil, "server.shutdown_timeout"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(test.file), 0o644); err != nil {
+				t.Fatalf("WriteFile returned error: %v", err)
+			}
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+			if _, err := Load(path); err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Load error = %v, want mention of %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestParseTTLOverrides(t *testing.T) {
+	got, err := ParseTTLOverrides(" scoreboardv2=10s, /*/events/*/odds=2m,")
+	if err != nil {
+		t.Fatalf("ParseTTLOverrides returned error: %v", err)
+	}
+	if len(got) != 2 || got["scoreboardv2"] != 10*time.Second || got["/*/events/*/odds"] != 2*time.Minute {
+		t.Errorf("ParseTTLOverrides = %v, want two overrides", got)
+	}
+
+	for _, bad := range []string{"bogus", "drafthistory=soon"} {
+		if _, err := ParseTTLOverrides(bad); err == nil {
+			t.Errorf("ParseTTLOverrides(%q) expected error", bad)
+		}
+	}
+}
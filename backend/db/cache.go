@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sports_api/config"
+	"time"
+)
+
+// ErrCacheMiss is returned by Get and TTL when a key is absent or expired.
+var ErrCacheMiss = errors.New("cache miss")
+
+// Cache is the key-value store upstream responses are cached in.
+type Cache interface {
+	// Get returns the value stored under key, or ErrCacheMiss.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for ttl; a zero ttl never expires.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// TTL returns how long key has left, zero if it never expires, or ErrCacheMiss.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Keys returns every live key starting with prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	// Ping reports whether the backend can currently be read and written.
+	Ping(ctx context.Context) error
+	// Close releases the backend's connections; the Cache must not be used afterwards.
+	Close() error
+}
+
+// Cache backends selectable with config.Cache.Backend
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendFile   = "file"
+)
+
+// NewCache creates the backend selected by cfg.
+func NewCache(cfg config.Cache) (Cache, error) {
+	switch cfg.Backend {
+	case BackendMemory:
+		return NewMemoryCache(cfg.MaxEntries), nil
+	case BackendRedis:
+		return NewRedisCache(cfg.RedisAddr), nil
+	case BackendFile:
+		return NewFileCache(cfg.Dir)
+	}
+	return nil, fmt.Errorf("unknown cache backend %q", cfg.Backend)
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sports_api/config"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestCacheBackends(t *testing.T) {
+	server := miniredis.RunT(t)
+	file, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache returned error: %v", err)
+	}
+
+	backends := map[string]Cache{
+		"memory": NewMemoryCache(0),
+		"redis":  NewRedisCache(server.Addr()),
+		"file":   file,
+	}
+	for name, cache := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if err := cache.Ping(ctx); err != nil {
+				t.Errorf("Ping returned error: %v", err)
+			}
+			if _, err := cache.Get(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+				t.Errorf("Get(missing) error = %v, want ErrCacheMiss", err)
+			}
+			if _, err := cache.TTL(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+				t.Errorf("TTL(missing) error = %v, want ErrCacheMiss", err)
+			}
+
+			if err := cache.Set(ctx, "key", []byte("value"), time.Hour); err != nil {
+				t.Fatalf("Set returned error: %v", err)
+			}
+			value, err := cache.Get(ctx, "key")
+			if err != nil || string(value) != "value" {
+				t.Errorf("Get = %q, %v, want value", value, err)
+			}
+			if ttl, err := cache.TTL(ctx, "key"); err != nil || ttl <= 59*time.Minute || ttl > time.Hour {
+				t.Errorf("TTL = %s, %v, want about 1h", ttl, err)
+			}
+
+			if err := cache.Set(ctx, "forever", []byte("value"), 0); err != nil {
+				t.Fatalf("Set returned error: %v", err)
+			}
+			if ttl, err := cache.TTL(ctx, "forever"); err != nil || ttl != 0 {
+				t.Errorf("TTL without expiry = %s, %v, want 0", ttl, err)
+			}
+
+			if err := cache.Set(ctx, "https://x/a*[b]?q=1", []byte("value"), time.Hour); err != nil {
+				t.Fatalf("Set returned error: %v", err)
+			}
+			keys, err := cache.Keys(ctx, "https://x/a*[")
+			if err != nil || len(keys) != 1 || keys[0] != "https://x/a*[b]?q=1" {
+				t.Errorf("Keys = %q, %v, want the one key with a literal glob prefix", keys, err)
+			}
+			if keys, err := cache.Keys(ctx, ""); err != nil || len(keys) != 3 {
+				t.Errorf("Keys(\"\") = %q, %v, want all 3 keys", keys, err)
+			}
+
+			if err := cache.Delete(ctx, "key"); err != nil {
+				t.Fatalf("Delete returned error: %v", err)
+			}
+			if _, err := cache.Get(ctx, "key"); !errors.Is(err, ErrCacheMiss) {
+				t.Errorf("Get after Delete error = %v, want ErrCacheMiss", err)
+			}
+			if err := cache.Delete(ctx, "key"); err != nil {
+				t.Errorf("Delete of missing key returned error: %v", err)
+			}
+			if err := cache.Close(); err != nil {
+				t.Errorf("Close returned error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPingReportsUnreachableBackends(t *testing.T) {
+	server := miniredis.RunT(t)
+	redis := NewRedisCache(server.Addr())
+	server.Close()
+	if err := redis.Ping(context.Background()); err == nil {
+		t.Error("Ping of a stopped Redis server succeeded")
+	}
+
+	file, err := NewFileCache(t.TempDir() + "/cache")
+	if err != nil {
+		t.Fatalf("NewFileCache returned error: %v", err)
+	}
+	if err := os.RemoveAll(file.Dir); err != nil {
+		t.Fatalf("RemoveAll returned error: %v", err)
+	}
+	if err := file.Ping(context.Background()); err == nil {
+		t.Error("Ping of a removed cache directory succeeded")
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+
+	_ = cache.Set(ctx, "a", []byte("1"), 0)
+	_ = cache.Set(ctx, "b", []byte("2"), 0)
+	_, _ = cache.Get(ctx, "a")
+	_ = cache.Set(ctx, "c", []byte("3"), 0)
+
+	if _, err := cache.Get(ctx, "b"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("Get(b) error = %v, want b evicted", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := cache.Get(ctx, key); err != nil {
+			t.Errorf("Get(%s) returned error: %v", key, err)
+		}
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	memory := NewMemoryCache(0)
+	memory.now = clock
+	file, _ := NewFileCache(t.TempDir())
+	file.now = clock
+
+	for name, cache := range map[string]Cache{"memory": memory, "file": file} {
+		now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		_ = cache.Set(ctx, "key", []byte("value"), time.Minute)
+		now = now.Add(time.Minute)
+		if _, err := cache.Get(ctx, "key"); !errors.Is(err, ErrCacheMiss) {
+			t.Errorf("%s: Get after expiry error = %v, want ErrCacheMiss", name, err)
+		}
+	}
+}
+
+func TestNewCacheRejectsUnknownBackend(t *testing.T) {
+	if _, err := NewCache(config.Cache{Backend: "memcached"}); err == nil {
+		t.Errorf("Expected error for unknown backend")
+	}
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileCache stores each key as a JSON file named after the key's SHA-256 hash.
+type FileCache struct {
+	Dir string
+	now func() time.Time
+}
+
+type fileEntry struct {
+	Key       string
+	Value     []byte
+	ExpiresAt time.Time // Zero never expires
+}
+
+// NewFileCache creates a FileCache in dir, creating the directory if needed.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("error creating cache directory: %w", err)
+	}
+	return &FileCache{Dir: dir, now: time.Now}, nil
+}
+
+// Get reads a value from disk
+func (f *FileCache) Get(ctx context.Context, key string) ([]byte, error) {
+	entry, err := f.read(key)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Value, nil
+}
+
+// Set writes a value to disk, replacing the file atomically
+func (f *FileCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := fileEntry{Key: key, Value: value}
+	if ttl > 0 {
+		entry.ExpiresAt = f.now().Add(ttl)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding cache entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(f.Dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+// Delete removes a value from disk
+func (f *FileCache) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(f.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing cache file: %w", err)
+	}
+	return nil
+}
+
+// TTL returns the time left before a value on disk expires
+func (f *FileCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	entry, err := f.read(key)
+	if err != nil {
+		return 0, err
+	}
+	if entry.ExpiresAt.IsZero() {
+		return 0, nil
+	}
+	return entry.ExpiresAt.Sub(f.now()), nil
+}
+
+// Keys lists keys on disk starting with prefix
+func (f *FileCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	files, err := os.ReadDir(f.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("error listing cache directory: %w", err)
+	}
+
+	var keys []string
+	now := f.now()
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".tmp-") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(f.Dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		var entry fileEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		if strings.HasPrefix(entry.Key, prefix) && (entry.ExpiresAt.IsZero() || now.Before(entry.ExpiresAt)) {
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys, nil
+}
+
+// Ping checks the cache directory is still writable
+func (f *FileCache) Ping(ctx context.Context) error {
+	tmp, err := os.CreateTemp(f.Dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("cache directory is not writable: %w", err)
+	}
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// Close does nothing; files are opened per operation
+func (f *FileCache) Close() error {
+	return nil
+}
+
+// read loads the entry for key, removing it if it has expired.
+func (f *FileCache) read(key string) (*fileEntry, error) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrCacheMiss
+		}
+		return nil, fmt.Errorf("error opening cache file: %w", err)
+	}
+
+	var entry fileEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("error reading cache file: %w", err)
+	}
+	if entry.Key != key {
+		return nil, ErrCacheMiss
+	}
+	if !entry.ExpiresAt.IsZero() && !f.now().Before(entry.ExpiresAt) {
+		_ = os.Remove(f.path(key))
+		return nil, ErrCacheMiss
+	}
+	return &entry, nil
+}
+
+func (f *FileCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(f.Dir, hex.EncodeToString(hash[:]))
+}
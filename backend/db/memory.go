@@ -0,0 +1,140 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryCache is an in-process LRU cache for development and tests.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // Front is most recently used
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // Zero never expires
+}
+
+// NewMemoryCache creates a MemoryCache holding at most maxEntries keys; zero means unbounded.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get retrieves a value, marking it as recently used
+func (m *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Set stores a value, evicting the least recently used key when full
+func (m *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{key: key, value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+
+	if elem, ok := m.entries[key]; ok {
+		elem.Value = entry
+		m.order.MoveToFront(elem)
+		return nil
+	}
+	m.entries[key] = m.order.PushFront(entry)
+	if m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		m.remove(m.order.Back())
+	}
+	return nil
+}
+
+// Delete removes a key
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.remove(elem)
+	}
+	return nil
+}
+
+// TTL returns the time left before a key expires
+func (m *MemoryCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok {
+		return 0, ErrCacheMiss
+	}
+	if entry.expiresAt.IsZero() {
+		return 0, nil
+	}
+	return entry.expiresAt.Sub(m.now()), nil
+}
+
+// Keys lists live keys starting with prefix
+func (m *MemoryCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var keys []string
+	for key, elem := range m.entries {
+		entry := elem.Value.(*memoryEntry)
+		if strings.HasPrefix(key, prefix) && (entry.expiresAt.IsZero() || now.Before(entry.expiresAt)) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Ping always succeeds; memory is always available
+func (m *MemoryCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing; there are no connections to release
+func (m *MemoryCache) Close() error {
+	return nil
+}
+
+// lookup returns the live entry for key, dropping it if it has expired.
+func (m *MemoryCache) lookup(key string) (*memoryEntry, bool) {
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !m.now().Before(entry.expiresAt) {
+		m.remove(elem)
+		return nil, false
+	}
+	m.order.MoveToFront(elem)
+	return entry, true
+}
+
+func (m *MemoryCache) remove(elem *list.Element) {
+	m.order.Remove(elem)
+	delete(m.entries, elem.Value.(*memoryEntry).key)
+}
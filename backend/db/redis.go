@@ -2,50 +2,90 @@ package db
 
 import (
 	"context"
-	"log"
-	"sync"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// RedisClientWrapper provides a global Redis client
+// RedisClientWrapper is a Cache backed by Redis
 type RedisClientWrapper struct {
 	client *redis.Client
 }
 
-// Global instance of RedisClientWrapper
-var (
-	redisInstance *RedisClientWrapper
-	once          sync.Once
-)
+// NewRedisCache creates a Redis-backed Cache for the server at addr
+func NewRedisCache(addr string) *RedisClientWrapper {
+	return &RedisClientWrapper{
+		client: redis.NewClient(&redis.Options{
+			Addr: addr,
+		}),
+	}
+}
 
-// InitRedis initializes the Redis client and ensures it's only set once
-func InitRedis() {
-	once.Do(func() {
-		redisInstance = &RedisClientWrapper{
-			client: redis.NewClient(&redis.Options{
-				Addr: "localhost:6379",
-			}),
-		}
-		log.Println("Redis client initialized")
-	})
+// Get retrieves a value from Redis
+func (r *RedisClientWrapper) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	return value, err
+}
+
+// Set stores a key-value pair in Redis
+func (r *RedisClientWrapper) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete removes a key from Redis
+func (r *RedisClientWrapper) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
 }
 
-// GetRedisClient provides access to the global Redis client
-func GetRedisClient() *RedisClientWrapper {
-	if redisInstance == nil {
-		InitRedis()
+// Keys lists keys starting with prefix using SCAN so Redis is never blocked
+func (r *RedisClientWrapper) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, escapeGlob(prefix)+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
 	}
-	return redisInstance
+	return keys, iter.Err()
 }
 
-// Save stores a key-value pair in Redis
-func (r *RedisClientWrapper) Save(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	return r.client.Set(ctx, key, value, expiration).Err()
+// escapeGlob escapes the characters Redis MATCH patterns treat specially.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\', '^', '-':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
 
-// Get retrieves a value from Redis
-func (r *RedisClientWrapper) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+// Ping checks the Redis server is reachable
+func (r *RedisClientWrapper) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Close closes the connections to Redis
+func (r *RedisClientWrapper) Close() error {
+	return r.client.Close()
+}
+
+// TTL returns the time left before a key expires in Redis
+func (r *RedisClientWrapper) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	switch ttl {
+	case -2: // Key does not exist
+		return 0, ErrCacheMiss
+	case -1: // Key has no expiry
+		return 0, nil
+	}
+	return ttl, nil
 }
@@ -0,0 +1,238 @@
+// Package fakenba is a fake stats.nba.com for integration tests and local
+// development. It serves resultSets-shaped payloads from embedded fixtures
+// for every endpoint in urls/nba and can simulate the upstream's failures.
+package fakenba
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Fault is a failure the fake injects instead of serving a fixture.
+type Fault string
+
+const (
+	FaultHTML        Fault = "html"    // 200 with an HTML error page, as stats.nba.com sends when it blocks a client
+	FaultRateLimit   Fault = "429"     // 429 Too Many Requests with Retry-After: 1
+	FaultServerError Fault = "500"     // 500 Internal Server Error
+	FaultTimeout     Fault = "timeout" // Never respond; the request hangs until the client gives up
+)
+
+// ParseFault parses html, 429, 500 or timeout.
+func ParseFault(value string) (Fault, error) {
+	switch fault := Fault(value); fault {
+	case FaultHTML, FaultRateLimit, FaultServerError, FaultTimeout:
+		return fault, nil
+	}
+	return "", fmt.Errorf("unknown fault %q, want html, 429, 500 or timeout", value)
+}
+
+// Prefix is the path the fake serves endpoints under, matching stats.nba.com/stats/.
+const Prefix = "/stats/"
+
+type faultRule struct {
+	fault     Fault
+	remaining int // Requests left to fail; negative fails forever
+}
+
+// Handler serves the fake API. The zero value is not usable; use NewHandler.
+type Handler struct {
+	mu       sync.Mutex
+	encoding string // Forced Content-Encoding; empty follows Accept-Encoding
+	faults   map[string]*faultRule
+	requests map[string]int
+	closed   chan struct{}
+	once     sync.Once
+}
+
+// NewHandler creates a Handler serving every endpoint from its fixture.
+func NewHandler() *Handler {
+	return &Handler{
+		faults:   make(map[string]*faultRule),
+		requests: make(map[string]int),
+		closed:   make(chan struct{}),
+	}
+}
+
+// SetFault makes the next count requests to endpoint fail with fault, or every
+// request when count is not positive. An empty endpoint applies to all endpoints.
+func (h *Handler) SetFault(endpoint string, fault Fault, count int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if count <= 0 {
+		count = -1
+	}
+	h.faults[endpoint] = &faultRule{fault: fault, remaining: count}
+}
+
+// ClearFaults removes every fault.
+func (h *Handler) ClearFaults() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.faults = make(map[string]*faultRule)
+}
+
+// SetEncoding forces the Content-Encoding of responses to gzip, deflate or
+// identity. Empty follows the request's Accept-Encoding.
+func (h *Handler) SetEncoding(encoding string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.encoding = encoding
+}
+
+// Requests returns how many requests endpoint has received, including failed ones.
+func (h *Handler) Requests(endpoint string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.requests[endpoint]
+}
+
+// Close releases requests hanging on FaultTimeout.
+func (h *Handler) Close() {
+	h.once.Do(func() { close(h.closed) })
+}
+
+// ServeHTTP serves /stats/<endpoint> from fixtures/<endpoint>.json.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint, ok := strings.CutPrefix(path.Clean(r.URL.Path), Prefix)
+	if !ok || strings.Contains(endpoint, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	fixture, err := fixtures.ReadFile("fixtures/" + endpoint + ".json")
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	h.requests[endpoint]++
+	fault := h.takeFault(endpoint)
+	encoding := h.encoding
+	h.mu.Unlock()
+
+	switch fault {
+	case FaultHTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		h.write(w, r, encoding, []byte("<html><head><title>Access Denied</title></head><body><h1>Access Denied</h1></body></html>"))
+		return
+	case FaultRateLimit:
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	case FaultServerError:
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	case FaultTimeout:
+		select {
+		case <-r.Context().Done():
+		case <-h.closed:
+		}
+		return
+	}
+
+	var payload struct {
+		ResultSets json.RawMessage `json:"resultSets"`
+	}
+	if err := json.Unmarshal(fixture, &payload); err != nil {
+		http.Error(w, fmt.Sprintf("bad fixture %s: %v", endpoint, err), http.StatusInternalServerError)
+		return
+	}
+	parameters := make(map[string]string)
+	for name := range r.URL.Query() {
+		parameters[name] = r.URL.Query().Get(name)
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"resource":   endpoint,
+		"parameters": parameters,
+		"resultSets": payload.ResultSets,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	h.write(w, r, encoding, body)
+}
+
+// takeFault returns the fault for endpoint, consuming one use. h.mu must be held.
+func (h *Handler) takeFault(endpoint string) Fault {
+	for _, key := range []string{endpoint, ""} {
+		rule, ok := h.faults[key]
+		if !ok || rule.remaining == 0 {
+			continue
+		}
+		if rule.remaining > 0 {
+			rule.remaining--
+		}
+		return rule.fault
+	}
+	return ""
+}
+
+// write sends body compressed with encoding, or with the first of gzip and
+// deflate the request accepts when encoding is empty.
+func (h *Handler) write(w http.ResponseWriter, r *http.Request, encoding string, body []byte) {
+	if encoding == "" {
+		for _, accepted := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+			if accepted = strings.TrimSpace(accepted); accepted == "gzip" || accepted == "deflate" {
+				encoding = accepted
+				break
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	switch encoding {
+	case "gzip":
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		zw.Close()
+	case "deflate":
+		zw := zlib.NewWriter(&buf)
+		zw.Write(body)
+		zw.Close()
+	default:
+		buf.Write(body)
+		encoding = ""
+	}
+	if encoding != "" {
+		w.Header().Set("Content-Encoding", encoding)
+	}
+	w.Write(buf.Bytes())
+}
+
+// Server is a running fake on a local port.
+type Server struct {
+	*Handler
+	*httptest.Server
+}
+
+// NewServer starts a fake stats.nba.com. Point an NBA client at BaseURL.
+func NewServer() *Server {
+	handler := NewHandler()
+	return &Server{Handler: handler, Server: httptest.NewServer(handler)}
+}
+
+// BaseURL is the fake's equivalent of https://stats.nba.com/stats/.
+func (s *Server) BaseURL() string {
+	return s.Server.URL + Prefix
+}
+
+// Close releases hanging requests and shuts the server down.
+func (s *Server) Close() {
+	s.Handler.Close()
+	s.Server.Close()
+}
@@ -0,0 +1,100 @@
+package fakenba
+
+import (
+	"context"
+	"errors"
+	"sports_api/apierror"
+	"sports_api/config"
+	"sports_api/globals/nba"
+	endpoints "sports_api/stats/endpoints/nba"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFakeClient(t *testing.T) (*Server, *nba.Client) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	cfg := config.Default()
+	cfg.NBA.BaseURL = server.BaseURL()
+	return server, nba.NewNBAClient(cfg, nil)
+}
+
+func TestServesEveryFixture(t *testing.T) {
+	_, client := newFakeClient(t)
+	files, err := fixtures.ReadDir("fixtures")
+	if err != nil {
+		t.Fatalf("ReadDir returned error: %v", err)
+	}
+	for _, file := range files {
+		endpoint := strings.TrimSuffix(file.Name(), ".json")
+		resp, err := client.NBAGetRequest(context.Background(), endpoint, map[string]string{"LeagueID": "00"}, "", nil)
+		if err != nil {
+			t.Errorf("%s returned error: %v", endpoint, err)
+			continue
+		}
+		dict, err := resp.GetNormalizedDict2()
+		if err != nil || len(dict) == 0 {
+			t.Errorf("%s result sets = %v, %v, want at least one", endpoint, dict, err)
+		}
+		if resource, _ := resp.GetResource(); resource != endpoint {
+			t.Errorf("%s resource = %q", endpoint, resource)
+		}
+	}
+}
+
+func TestRunsEndpointsAgainstFake(t *testing.T) {
+	_, client := newFakeClient(t)
+	players, err := endpoints.GetAllNBAPlayers(context.Background(), client)
+	if err != nil {
+		t.Fatalf("GetAllNBAPlayers returned error: %v", err)
+	}
+	if len(players) != 4 || players[0].Name != "LeBron James" || players[0].TeamID != 1610612747 {
+		t.Errorf("GetAllNBAPlayers = %+v, want the 4 fixture players", players)
+	}
+	if logs, err := endpoints.GetAllNBAPlayerStatsFullSeason(context.Background(), client); err != nil || len(logs) != 4 {
+		t.Errorf("GetAllNBAPlayerStatsFullSeason returned %d logs, %v, want 4", len(logs), err)
+	}
+}
+
+func TestEncodings(t *testing.T) {
+	server, client := newFakeClient(t)
+	for _, encoding := range []string{"gzip", "deflate", "identity"} {
+		server.SetEncoding(encoding)
+		resp, err := client.NBAGetRequest(context.Background(), "commonteamyears", nil, "", nil)
+		if err != nil {
+			t.Errorf("%s response returned error: %v", encoding, err)
+			continue
+		}
+		if got := resp.Headers.Get("Content-Encoding"); encoding != "identity" && got != encoding {
+			t.Errorf("Content-Encoding = %q, want %s", got, encoding)
+		}
+	}
+}
+
+func TestFaults(t *testing.T) {
+	server, client := newFakeClient(t)
+	ctx := context.Background()
+
+	server.SetFault("drafthistory", FaultHTML, 1)
+	if _, err := client.NBAGetRequest(ctx, "drafthistory", nil, "", nil); !errors.Is(err, apierror.Blocked) || !strings.Contains(err.Error(), "HTML error page") {
+		t.Errorf("HTML fault error = %v, want a Blocked HTML error page", err)
+	}
+
+	// One 429 is retried after its Retry-After and then succeeds
+	server.SetFault("franchisehistory", FaultRateLimit, 1)
+	if _, err := client.NBAGetRequest(ctx, "franchisehistory", nil, "", nil); err != nil {
+		t.Errorf("request after a 429 returned error: %v", err)
+	}
+	if n := server.Requests("franchisehistory"); n != 2 {
+		t.Errorf("franchisehistory got %d requests, want 2", n)
+	}
+
+	server.SetFault("scoreboardv2", FaultTimeout, 0)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.NBAGetRequest(timeoutCtx, "scoreboardv2", nil, "", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout fault error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -1,37 +1,21 @@
 package nba
 
 import (
-	"compress/gzip"
-	"compress/zlib"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"net/url"
+	"log/slog"
+	"sports_api/apierror"
+	"sports_api/config"
 	"sports_api/db"
+	"sports_api/globals/upstream"
+	endpoints "sports_api/urls/nba"
 	"sync"
 	"time"
 )
 
-// NBASession is a globally accessible instance of NBAClient.
-var NBASession *Client
-
 // NBAResponse represents the API response structure.
 type NBAResponse struct {
-	Status     string
-	StatusCode int
-	Data       interface{}
-	URL        string
-	Headers    http.Header
-}
-
-func (r *NBAResponse) GetData() (interface{}, error) {
-	if r.Data == nil {
-		return nil, fmt.Errorf("No Data")
-	}
-	return r.Data, nil
+	upstream.Response
 }
 
 // GetParameters extracts the parameters from the response.
@@ -47,7 +31,7 @@ func (r *NBAResponse) GetParameters() (map[string]interface{}, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("parameters not found in response")
+	return nil, apierror.New(apierror.Decode, "parameters not found in response")
 }
 
 // GetResource extracts the resource field from the response.
@@ -63,11 +47,14 @@ func (r *NBAResponse) GetResource() (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("resource not found in response")
+	return "", apierror.New(apierror.Decode, "resource not found in response")
 }
 
 // GetResultSets extracts the resultSets from the response.
 func (r *NBAResponse) GetResultSets() ([]interface{}, error) {
+	if r.isNil() {
+		return nil, apierror.New(apierror.Internal, "no response")
+	}
 	data, err := r.GetData()
 	if err != nil {
 		return nil, err
@@ -79,7 +66,7 @@ func (r *NBAResponse) GetResultSets() ([]interface{}, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("resultSets not found in response")
+	return nil, apierror.New(apierror.Decode, "resultSets not found in response")
 }
 
 func (r *NBAResponse) isNil() bool {
@@ -94,7 +81,7 @@ func (r *NBAResponse) GetNormalizedDict() (map[string][]map[string]interface{},
 	}
 
 	if len(resultSets) == 0 {
-		return nil, fmt.Errorf("resultSets are empty")
+		return nil, apierror.New(apierror.Decode, "resultSets are empty")
 	}
 
 	// Map to store processed data keyed by resultSet name
@@ -206,7 +193,7 @@ func (r *NBAResponse) GetNormalizedDict2() (map[string][]map[string]interface{},
 	}
 
 	if len(resultSets) == 0 {
-		return nil, fmt.Errorf("resultSets are empty")
+		return nil, apierror.New(apierror.Decode, "resultSets are empty")
 	}
 
 	// Ordered map to store processed data keyed by resultSet name
@@ -292,13 +279,13 @@ func (r *NBAResponse) GetAllHeaders() (map[string][]string, error) {
 	}
 
 	if len(resultSets) == 0 {
-		return nil, fmt.Errorf("resultSets are empty")
+		return nil, apierror.New(apierror.Decode, "resultSets are empty")
 	}
 	// If only one resultSet, process it directly
 	if len(resultSets) == 1 {
 		rs, ok := resultSets[0].(map[string]interface{})
 		if !ok {
-			return nil, fmt.Errorf("invalid resultSet format")
+			return nil, apierror.New(apierror.Decode, "invalid resultSet format")
 		}
 		return processSingleHeaders(rs)
 	}
@@ -314,13 +301,13 @@ func (r *NBAResponse) GetResultSetNames() ([]string, error) {
 	}
 
 	if len(resultSets) == 0 {
-		return nil, fmt.Errorf("resultSets are empty")
+		return nil, apierror.New(apierror.Decode, "resultSets are empty")
 	}
 
 	if len(resultSets) == 1 {
 		rs, ok := resultSets[0].(map[string]interface{})
 		if !ok {
-			return nil, fmt.Errorf("invalid resultSet format")
+			return nil, apierror.New(apierror.Decode, "invalid resultSet format")
 		}
 		name, exists := rs["name"].(string)
 		if !exists {
@@ -341,13 +328,13 @@ func (r *NBAResponse) GetRowSets() (map[string][][]interface{}, error) {
 	}
 
 	if len(resultSets) == 0 {
-		return nil, fmt.Errorf("resultSets are empty")
+		return nil, apierror.New(apierror.Decode, "resultSets are empty")
 	}
 
 	if len(resultSets) == 1 {
 		rs, ok := resultSets[0].(map[string]interface{})
 		if !ok {
-			return nil, fmt.Errorf("invalid resultSet format")
+			return nil, apierror.New(apierror.Decode, "invalid resultSet format")
 		}
 		return processSingleRowSet(rs)
 	}
@@ -372,7 +359,7 @@ func (r *NBAResponse) GetHeaders() ([]interface{}, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("headers not found in resultSets")
+	return nil, apierror.New(apierror.Decode, "headers not found in resultSets")
 }
 
 // GetRowSet extracts the rowSet data from the first resultSet.
@@ -390,209 +377,82 @@ func (r *NBAResponse) GetRowSet() ([]interface{}, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("rowSet not found in resultSets")
+	return nil, apierror.New(apierror.Decode, "rowSet not found in resultSets")
 }
 
-// NBAClient wraps an HTTP client with default headers for NBA API requests.
+// Client wraps the shared upstream client with NBA defaults.
 type Client struct {
-	HTTPClient     *http.Client
-	BaseURL        string
-	DefaultHeaders map[string]string
-	Proxy          string
+	*upstream.Client
+	Season     string // Current NBA season, e.g. 2024-25
+	WNBASeason string // Current WNBA season
 }
 
-// NewNBAClient initializes and returns an NBAClient instance.
-func NewNBAClient() *Client {
-	return &Client{
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		BaseURL: "https://stats.nba.com/stats/",
-		DefaultHeaders: map[string]string{
+// NewNBAClient initializes and returns an NBAClient instance configured by cfg.
+// A nil cache disables response caching.
+func NewNBAClient(cfg *config.Config, cache db.Cache) *Client {
+	c := &Client{
+		Client: upstream.NewClient("NBA", cfg.NBA.BaseURL, cache, map[string]string{
 			"Host":               "stats.nba.com",
 			"User-Agent":         "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:72.0) Gecko/20100101 Firefox/72.0",
 			"Accept":             "application/json, text/plain, */*",
 			"Accept-Language":    "en-US,en;q=0.5",
-			"Accept-Encoding":    "gzip, deflate, br",
+			"Accept-Encoding":    upstream.AcceptEncoding(),
 			"x-nba-stats-origin": "stats",
 			"x-nba-stats-token":  "true",
 			"Connection":         "keep-alive",
 			"Referer":            "https://stats.nba.com/",
 			"Pragma":             "no-cache",
 			"Cache-Control":      "no-cache",
-		},
-	}
-}
-
-// SetProxy sets a proxy for the client.
-func (c *Client) SetProxy(proxyURL string) {
-	c.Proxy = proxyURL
+		}),
+		Season:     cfg.Seasons.NBA,
+		WNBASeason: cfg.Seasons.WNBA,
+	}
+	if err := c.Configure(cfg.NBA); err != nil {
+		slog.Error("failed to configure NBA client", "error", err)
+	}
+	c.EndpointCacheTTLs = map[string]time.Duration{
+		endpoints.ScoreboardV2:                      upstream.LiveTTL,
+		endpoints.GameRotation:                      upstream.LiveTTL,
+		endpoints.PlayerGameLog:                     upstream.SeasonTTL,
+		endpoints.PlayerGameLogs:                    upstream.SeasonTTL,
+		endpoints.CumulativeStatsPlayer:             upstream.SeasonTTL,
+		endpoints.CumulativeStatsPlayerGames:        upstream.SeasonTTL,
+		endpoints.CumulativeStatsTeam:               upstream.SeasonTTL,
+		endpoints.CumulativeStatsTeamGames:          upstream.SeasonTTL,
+		endpoints.AssistLeaders:                     upstream.SeasonTTL,
+		endpoints.AssistTracker:                     upstream.SeasonTTL,
+		endpoints.DefenseHub:                        upstream.SeasonTTL,
+		endpoints.CommonPlayoffSeries:               upstream.SeasonTTL,
+		endpoints.CommonAllPlayer:                   upstream.RosterTTL,
+		endpoints.CommonPlayerInfo:                  upstream.RosterTTL,
+		endpoints.CommonTeamRoster:                  upstream.RosterTTL,
+		endpoints.DraftHistory:                      upstream.HistoricalTTL,
+		endpoints.DraftBoard:                        upstream.HistoricalTTL,
+		endpoints.DraftCombineDrillResults:          upstream.HistoricalTTL,
+		endpoints.DraftCombineNonStationaryShooting: upstream.HistoricalTTL,
+		endpoints.DraftCombinePlayerAnthro:          upstream.HistoricalTTL,
+		endpoints.DraftCombineSpotShooting:          upstream.HistoricalTTL,
+		endpoints.DraftCombineStats:                 upstream.HistoricalTTL,
+		endpoints.FranchiseHistory:                  upstream.HistoricalTTL,
+		endpoints.FranchiseLeaders:                  upstream.HistoricalTTL,
+		endpoints.FranchisePlayers:                  upstream.HistoricalTTL,
+		endpoints.AllTimeLeadersGrids:               upstream.HistoricalTTL,
+		endpoints.CommonTeamYears:                   upstream.HistoricalTTL,
+	}
+	// Full-season pulls are slow enough that users should never wait on a refetch
+	c.EndpointStaleWhileRevalidate = map[string]time.Duration{
+		endpoints.PlayerGameLogs:  24 * time.Hour,
+		endpoints.CommonAllPlayer: 24 * time.Hour,
+	}
+	c.CacheTTLOverrides = cfg.Cache.TTLOverrides
+	return c
 }
 
 // NBA Get Request constructs and executes an API request.
-func (c *Client) NBAGetRequest(endpoint string, params map[string]string, referer string, customHeaders map[string]string) (*NBAResponse, error) {
-	ctx := context.Background()
-
-	fullURL, err := prepareURL(c.BaseURL, endpoint, params)
-	if err != nil {
-		return nil, fmt.Errorf("error constructing request URL: %w", err)
-	}
-
-	redisClient := db.GetRedisClient()
-
-	// Check if response is cached in Redis
-	cachedData, err := redisClient.Get(ctx, fullURL)
-	if err == nil {
-		// If cache exists, parse it and return
-		var cachedResponse NBAResponse
-		err := json.Unmarshal([]byte(cachedData), &cachedResponse)
-		if err == nil {
-			log.Println("Cache hit for URL:", fullURL)
-			return &cachedResponse, nil
-		}
-		log.Println("Cache data invalid, making new request")
-	} else {
-		log.Println("Cache miss for URL:", fullURL)
-	}
-
-	// Create request
-	req, err := http.NewRequest("GET", fullURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	// Set headers
-	headers := make(map[string]string)
-	for k, v := range c.DefaultHeaders {
-		headers[k] = v // Copy default headers
-	}
-	if customHeaders != nil {
-		for k, v := range customHeaders {
-			headers[k] = v // Override with custom headers
-		}
-	}
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-
-	// Set Referer if provided
-	if referer != "" {
-		req.Header.Set("Referer", referer)
-	}
-
-	// Execute request
-	resp, err := c.HTTPClient.Do(req)
-
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %e", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
-	}
-	data, err := parseResponse(resp.Body, resp.Header)
+func (c *Client) NBAGetRequest(ctx context.Context, endpoint string, params map[string]string, referer string, customHeaders map[string]string) (*NBAResponse, error) {
+	resp, err := c.Get(ctx, endpoint, params, referer, customHeaders)
 	if err != nil {
 		return nil, err
 	}
-	response := &NBAResponse{
-		StatusCode: resp.StatusCode,
-		Status:     resp.Status,
-		Data:       data,
-		URL:        fullURL,
-		Headers:    resp.Header,
-	}
-
-	// Serialize response to JSON for caching
-	responseJSON, err := json.Marshal(response)
-	if err == nil {
-		// Save to Redis with a 1-day expiration
-		err = redisClient.Save(ctx, fullURL, responseJSON, 24*time.Hour)
-		if err != nil {
-			log.Println("Failed to cache response in Redis:", err)
-		}
-	}
-
-	return response, nil
-}
-
-// parseResponse reads and decodes the HTTP response body based on content encoding.
-func parseResponse(body io.ReadCloser, header http.Header) (interface{}, error) {
-	defer func() {
-		if err := body.Close(); err != nil {
-			fmt.Println("Warning: failed to close response body:", err)
-		}
-	}()
-
-	var reader io.Reader = body
-	contentEncoding := header.Get("Content-Encoding")
-
-	switch contentEncoding {
-	case "gzip":
-		gzReader, err := gzip.NewReader(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-		defer func() {
-			if err := gzReader.Close(); err != nil {
-				fmt.Println("Warning: failed to close gzip reader:", err)
-			}
-		}()
-		reader = gzReader
-	case "deflate":
-		zlibReader, err := zlib.NewReader(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create zlib reader: %w", err)
-		}
-		defer func() {
-			if err := zlibReader.Close(); err != nil {
-				fmt.Println("Warning: failed to close zlib reader:", err)
-			}
-		}()
-		reader = zlibReader
-	}
-
-	if header.Get("Content-Type") == "text/html; charset=utf-8" {
-		htmlContent, err := io.ReadAll(reader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read HTML response: %w", err)
-		}
-		return string(htmlContent), fmt.Errorf("NBA API returned HTML error page: %s", string(htmlContent))
-	}
-
-	// Decode JSON with UseNumber to avoid float precision issues
-	decoder := json.NewDecoder(reader)
-	decoder.UseNumber()
-
-	var result interface{}
-	if err := decoder.Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
-	}
-
-	return result, nil
-}
-
-// PrepareURL constructs a full URL with query parameters.
-func prepareURL(baseURL string, endpoint string, params map[string]string) (string, error) {
-	// Parse the base URL
-	u, err := url.Parse(baseURL + endpoint)
-	if err != nil {
-		return "", fmt.Errorf("error parsing base URL: %w", err)
-	}
-
-	if err != nil {
-		return "", fmt.Errorf("error parsing base URL: %w", err)
-	}
-
-	// Add query parameters
-	q := u.Query()
-	for key, value := range params {
-		q.Set(key, value)
-	}
-	u.RawQuery = q.Encode()
-
-	return u.String(), nil
-}
-
-func init() {
-	NBASession = NewNBAClient()
+	return &NBAResponse{Response: *resp}, nil
 }
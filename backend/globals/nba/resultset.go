@@ -0,0 +1,336 @@
+package nba
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sports_api/apierror"
+	"sports_api/logging"
+	"strconv"
+	"strings"
+)
+
+// ColumnError reports the columns of a result set that do not line up with
+// the struct it is decoded into.
+type ColumnError struct {
+	ResultSet string
+	Missing   []string // Identifying columns the result set lacks
+	Unknown   []string // Columns of the result set that no field maps
+}
+
+func (e *ColumnError) Error() string {
+	msg := fmt.Sprintf("result set %s is missing columns %s", e.ResultSet, strings.Join(e.Missing, ", "))
+	if len(e.Unknown) > 0 {
+		msg += fmt.Sprintf(" (unmapped columns: %s)", strings.Join(e.Unknown, ", "))
+	}
+	return msg
+}
+
+// Kind classifies a column mismatch as an unexpected upstream response.
+func (e *ColumnError) Kind() apierror.Kind {
+	return apierror.Decode
+}
+
+// Is matches apierror.Decode.
+func (e *ColumnError) Is(target error) bool {
+	return target == apierror.Decode
+}
+
+// ColumnMismatch reports the columns of a result set that do not line up
+// with the struct it was decoded into but did not fail the decode.
+type ColumnMismatch struct {
+	ResultSet string
+	Type      string   // The struct decoded into
+	Absent    []string // Columns the result set lacks, whose fields were left at the zero value
+	Unknown   []string // Columns of the result set that no field maps
+}
+
+// Log logs absent columns as a warning and unknown ones at debug level with
+// ctx's logger. A nil mismatch logs nothing.
+func (m *ColumnMismatch) Log(ctx context.Context) {
+	if m == nil {
+		return
+	}
+	logger := logging.FromContext(ctx).With("result_set", m.ResultSet, "type", m.Type)
+	if len(m.Absent) > 0 {
+		logger.WarnContext(ctx, "result set is missing columns", "columns", m.Absent)
+	}
+	if len(m.Unknown) > 0 {
+		logger.DebugContext(ctx, "result set has unmapped columns", "columns", m.Unknown)
+	}
+}
+
+// column maps a result set column to the struct field it is decoded into.
+type column struct {
+	name  string
+	index int   // Position in the result set's headers
+	field []int // Index sequence of the struct field, through any embedded structs
+}
+
+// DecodeResultSet decodes the rows of the result set called name into T, a
+// struct whose json tags name the columns. Headers are matched to fields once
+// and every row is assigned in a single pass. Numbers may arrive as
+// json.Number, float64 or numeric strings and are converted to each field's
+// type; nulls leave fields at their zero value.
+//
+// Untagged fields are left alone, except that the fields of untagged embedded
+// structs are decoded as if they were T's own; embedded pointers are rejected.
+// A missing identifying column, one named *_ID such as PLAYER_ID or GAME_ID,
+// fails the decode with a *ColumnError unless its field is tagged omitempty.
+// Other missing columns leave their fields at the zero value and, like
+// columns no field maps, are returned in a *ColumnMismatch, which is nil when
+// the columns line up exactly.
+func DecodeResultSet[T any](resp *NBAResponse, name string) ([]T, *ColumnMismatch, error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("cannot decode result set %s into %s: not a struct", name, typ)
+	}
+	headers, rows, err := resp.resultSet(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns, mismatch, err := mapColumns(typ, name, headers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decoded := make([]T, len(rows))
+	for i, row := range rows {
+		values, _ := row.([]interface{})
+		if len(values) != len(headers) {
+			return nil, nil, apierror.New(apierror.Decode, "result set %s row %d has %d values for %d columns", name, i, len(values), len(headers))
+		}
+		v := reflect.ValueOf(&decoded[i]).Elem()
+		for _, col := range columns {
+			if err := assign(v.FieldByIndex(col.field), values[col.index]); err != nil {
+				return nil, nil, apierror.New(apierror.Decode, "result set %s row %d column %s: %w", name, i, col.name, err)
+			}
+		}
+	}
+	return decoded, mismatch, nil
+}
+
+// resultSet returns the headers and rows of the result set called name.
+func (r *NBAResponse) resultSet(name string) ([]string, []interface{}, error) {
+	resultSets, err := r.GetResultSets()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, resultSet := range resultSets {
+		rs, ok := resultSet.(map[string]interface{})
+		if !ok || rs["name"] != name {
+			continue
+		}
+		rawHeaders, _ := rs["headers"].([]interface{})
+		rows, ok := rs["rowSet"].([]interface{})
+		if !ok {
+			return nil, nil, apierror.New(apierror.Decode, "result set %s has no rowSet", name)
+		}
+		headers := make([]string, len(rawHeaders))
+		for i, header := range rawHeaders {
+			headers[i] = fmt.Sprint(header)
+		}
+		return headers, rows, nil
+	}
+	return nil, nil, apierror.New(apierror.Decode, "result set %s not found in response", name)
+}
+
+// mapColumns matches the tagged fields of typ to headers.
+func mapColumns(typ reflect.Type, name string, headers []string) ([]column, *ColumnMismatch, error) {
+	positions := make(map[string]int, len(headers))
+	for i, header := range headers {
+		positions[header] = i
+	}
+	fields, err := taggedFields(typ, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot decode result set %s into %s: %w", name, typ, err)
+	}
+
+	var columns []column
+	var missing, absent []string
+	mapped := make(map[string]bool, len(headers))
+	for _, field := range fields {
+		tag := field.Tag.Get("json")
+		colName, options, _ := strings.Cut(tag, ",")
+		if colName == "" {
+			colName = field.Name
+		}
+		position, ok := positions[colName]
+		if !ok {
+			switch {
+			case slices.Contains(strings.Split(options, ","), "omitempty"):
+			case isIdentifier(colName):
+				missing = append(missing, colName)
+			default:
+				absent = append(absent, colName)
+			}
+			continue
+		}
+		mapped[colName] = true
+		columns = append(columns, column{name: colName, index: position, field: field.Index})
+	}
+
+	var unknown []string
+	for _, header := range headers {
+		if !mapped[header] {
+			unknown = append(unknown, header)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, &ColumnError{ResultSet: name, Missing: missing, Unknown: unknown}
+	}
+	if len(absent) > 0 || len(unknown) > 0 {
+		return columns, &ColumnMismatch{ResultSet: name, Type: typ.String(), Absent: absent, Unknown: unknown}, nil
+	}
+	return columns, nil, nil
+}
+
+// taggedFields returns the exported fields of typ with a json tag other than
+// "-", including those of untagged embedded structs, with Index set relative
+// to the outermost struct. index is the path to typ itself.
+func taggedFields(typ reflect.Type, index []int) ([]reflect.StructField, error) {
+	var fields []reflect.StructField
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		field.Index = append(slices.Clone(index), i)
+		tag, tagged := field.Tag.Lookup("json")
+		if field.Anonymous && !tagged {
+			switch field.Type.Kind() {
+			case reflect.Struct:
+				embedded, err := taggedFields(field.Type, field.Index)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, embedded...)
+			case reflect.Pointer:
+				return nil, fmt.Errorf("embedded pointer field %s is not supported", field.Name)
+			}
+			continue
+		}
+		if !tagged || tag == "-" || !field.IsExported() {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// isIdentifier reports whether column identifies the row, such as PLAYER_ID.
+func isIdentifier(column string) bool {
+	return strings.HasSuffix(column, "_ID")
+}
+
+// assign sets field from a value decoded from JSON, converting numbers to the
+// field's type.
+func assign(field reflect.Value, value interface{}) error {
+	if value == nil || value == "" && isNumeric(field.Kind()) {
+		field.SetZero()
+		return nil
+	}
+
+	switch kind := field.Kind(); {
+	case kind == reflect.Pointer:
+		elem := reflect.New(field.Type().Elem())
+		if err := assign(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+	case kind == reflect.Interface && reflect.TypeOf(value).AssignableTo(field.Type()):
+		field.Set(reflect.ValueOf(value))
+	case kind == reflect.String:
+		switch v := value.(type) {
+		case string:
+			field.SetString(v)
+		case json.Number:
+			field.SetString(v.String())
+		case float64:
+			field.SetString(strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return fmt.Errorf("cannot convert %T to %s", value, field.Type())
+		}
+	case kind == reflect.Bool:
+		if b, ok := value.(bool); ok {
+			field.SetBool(b)
+			return nil
+		}
+		n, err := toFloat(value) // Flags are often sent as 0 or 1
+		if err != nil {
+			return err
+		}
+		field.SetBool(n != 0)
+	case field.CanInt():
+		n, err := toInt(value)
+		if err != nil {
+			return err
+		}
+		if field.OverflowInt(n) {
+			return fmt.Errorf("%d overflows %s", n, field.Type())
+		}
+		field.SetInt(n)
+	case field.CanUint():
+		n, err := toInt(value)
+		if err != nil {
+			return err
+		}
+		if n < 0 || field.OverflowUint(uint64(n)) {
+			return fmt.Errorf("%d overflows %s", n, field.Type())
+		}
+		field.SetUint(uint64(n))
+	case field.CanFloat():
+		f, err := toFloat(value)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+func isNumeric(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// numberText returns the text of a number sent as a json.Number, float64 or string.
+func numberText(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case string:
+		return strings.TrimSpace(v), nil
+	}
+	return "", fmt.Errorf("cannot convert %T to a number", value)
+}
+
+func toFloat(value interface{}) (float64, error) {
+	text, err := numberText(value)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+// toInt converts value to an integer, accepting floats with no fractional part.
+func toInt(value interface{}) (int64, error) {
+	text, err := numberText(value)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("%s is not an integer", text)
+	}
+	return int64(f), nil
+}
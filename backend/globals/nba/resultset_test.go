@@ -0,0 +1,146 @@
+package nba
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sports_api/apierror"
+	"sports_api/globals/upstream"
+	"strings"
+	"testing"
+)
+
+type testRow struct {
+	ID       int      `json:"PERSON_ID"`
+	Name     string   `json:"DISPLAY_FIRST_LAST"`
+	FromYear string   `json:"FROM_YEAR"`
+	Minutes  int      `json:"MIN"`
+	FGPct    float64  `json:"FG_PCT"`
+	PlusMin  *float64 `json:"PLUS_MINUS"`
+	Active   bool     `json:"ROSTERSTATUS"`
+	Odds     string   `json:"odds,omitempty"`
+	Note     string
+}
+
+func testResponse(t *testing.T, body string) *NBAResponse {
+	t.Helper()
+	data, err := upstream.DecodeBody("NBA", []byte(body), http.Header{})
+	if err != nil {
+		t.Fatalf("DecodeBody returned error: %v", err)
+	}
+	return &NBAResponse{Response: upstream.Response{Data: data}}
+}
+
+func TestDecodeResultSet(t *testing.T) {
+	resp := testResponse(t, `{"resultSets": [
+		{"name": "Other", "headers": ["X"], "rowSet": [[1]]},
+		{"name": "Players",
+		 "headers": ["PERSON_ID", "DISPLAY_FIRST_LAST", "FROM_YEAR", "MIN", "FG_PCT", "PLUS_MINUS", "ROSTERSTATUS", "PLAYERCODE"],
+		 "rowSet": [
+			[2544, "LeBron James", 2003, 35.0, 0.525, -4, 1, "lebron_james"],
+			[1630173, "Precious Achiuwa", "2020", "", null, null, 0, null]
+		 ]}
+	]}`)
+
+	rows, mismatch, err := DecodeResultSet[testRow](resp, "Players")
+	if err != nil {
+		t.Fatalf("DecodeResultSet returned error: %v", err)
+	}
+	if mismatch == nil || len(mismatch.Absent) != 0 || !slices.Equal(mismatch.Unknown, []string{"PLAYERCODE"}) {
+		t.Errorf("mismatch = %+v, want PLAYERCODE unmapped", mismatch)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("decoded %d rows, want 2", len(rows))
+	}
+	lebron := rows[0]
+	if lebron.ID != 2544 || lebron.Name != "LeBron James" || lebron.FromYear != "2003" || lebron.Minutes != 35 ||
+		lebron.FGPct != 0.525 || lebron.PlusMin == nil || *lebron.PlusMin != -4 || !lebron.Active {
+		t.Errorf("row 0 = %+v, want every column converted", lebron)
+	}
+	if achiuwa := rows[1]; achiuwa.FromYear != "2020" || achiuwa.Minutes != 0 || achiuwa.PlusMin != nil || achiuwa.Active {
+		t.Errorf("row 1 = %+v, want nulls and blanks left at zero", achiuwa)
+	}
+}
+
+func TestDecodeResultSetMissingColumns(t *testing.T) {
+	// Only PERSON_ID identifies the row; the other missing columns stay zero
+	resp := testResponse(t, `{"resultSets": [{"name": "Players",
+		"headers": ["PERSON_ID", "DISPLAY_FIRST_LAST"],
+		"rowSet": [[2544, "LeBron James"]]}]}`)
+
+	rows, mismatch, err := DecodeResultSet[testRow](resp, "Players")
+	if err != nil {
+		t.Fatalf("DecodeResultSet returned error: %v", err)
+	}
+	if want := []string{"FROM_YEAR", "MIN", "FG_PCT", "PLUS_MINUS", "ROSTERSTATUS"}; mismatch == nil || !slices.Equal(mismatch.Absent, want) || len(mismatch.Unknown) != 0 {
+		t.Errorf("mismatch = %+v, want absent columns %q", mismatch, want)
+	}
+	if len(rows) != 1 || rows[0].ID != 2544 || rows[0].Name != "LeBron James" || rows[0].Minutes != 0 || rows[0].PlusMin != nil {
+		t.Errorf("rows = %+v, want the present columns decoded and the rest zero", rows)
+	}
+}
+
+type teamColumns struct {
+	TeamID int    `json:"TEAM_ID"`
+	Team   string `json:"TEAM_ABBREVIATION"`
+}
+
+type embeddedRow struct {
+	teamColumns
+	ID int `json:"PERSON_ID"`
+}
+
+func TestDecodeResultSetEmbeddedFields(t *testing.T) {
+	resp := testResponse(t, `{"resultSets": [{"name": "Players",
+		"headers": ["PERSON_ID", "TEAM_ID", "TEAM_ABBREVIATION"],
+		"rowSet": [[2544, 1610612747, "LAL"]]}]}`)
+
+	rows, mismatch, err := DecodeResultSet[embeddedRow](resp, "Players")
+	if err != nil {
+		t.Fatalf("DecodeResultSet returned error: %v", err)
+	}
+	if mismatch != nil {
+		t.Errorf("mismatch = %+v, want the columns to line up", mismatch)
+	}
+	if len(rows) != 1 || rows[0].ID != 2544 || rows[0].TeamID != 1610612747 || rows[0].Team != "LAL" {
+		t.Errorf("rows = %+v, want the embedded struct's columns decoded", rows)
+	}
+
+	type pointerRow struct {
+		*teamColumns
+		ID int `json:"PERSON_ID"`
+	}
+	if _, _, err := DecodeResultSet[pointerRow](resp, "Players"); err == nil || !strings.Contains(err.Error(), "embedded pointer field teamColumns is not supported") {
+		t.Errorf("error = %v, want embedded pointers rejected", err)
+	}
+}
+
+func TestDecodeResultSetErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"missing result set", `{"resultSets": [{"name": "Other", "headers": [], "rowSet": []}]}`, "result set Players not found in response"},
+		{"missing identifying column", `{"resultSets": [{"name": "Players", "headers": ["DISPLAY_FIRST_LAST", "TEAM_ID"], "rowSet": []}]}`,
+			"result set Players is missing columns PERSON_ID (unmapped columns: TEAM_ID)"},
+		{"fractional int", `{"resultSets": [{"name": "Players",
+			"headers": ["PERSON_ID", "DISPLAY_FIRST_LAST", "FROM_YEAR", "MIN", "FG_PCT", "PLUS_MINUS", "ROSTERSTATUS"],
+			"rowSet": [[1, "A", "2020", 12.5, 0.5, 1, 1]]}]}`, "result set Players row 0 column MIN: 12.5 is not an integer"},
+		{"short row", `{"resultSets": [{"name": "Players",
+			"headers": ["PERSON_ID", "DISPLAY_FIRST_LAST", "FROM_YEAR", "MIN", "FG_PCT", "PLUS_MINUS", "ROSTERSTATUS"],
+			"rowSet": [[1, "A"]]}]}`, "result set Players row 0 has 2 values for 7 columns"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := DecodeResultSet[testRow](testResponse(t, test.body), "Players")
+			if err == nil || err.Error() != test.want {
+				t.Fatalf("error = %v, want %q", err, test.want)
+			}
+			if !errors.Is(err, apierror.Decode) {
+				t.Errorf("error kind = %v, want Decode", apierror.KindOf(err))
+			}
+		})
+	}
+}
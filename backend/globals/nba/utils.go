@@ -2,6 +2,7 @@ package nba
 
 import (
 	"fmt"
+	"sports_api/apierror"
 	"sync"
 )
 
@@ -16,7 +17,7 @@ func processSingleRowSet(rs map[string]interface{}) (map[string][][]interface{},
 
 	rowSet, ok := rs["rowSet"].([]interface{})
 	if !ok {
-		return nil, fmt.Errorf("rowSet missing in resultSet")
+		return nil, apierror.New(apierror.Decode, "rowSet missing in resultSet")
 	}
 
 	rows := make([][]interface{}, len(rowSet))
@@ -116,7 +117,7 @@ func processSingleHeaders(rs map[string]interface{}) (map[string][]string, error
 
 	headers, ok := rs["headers"].([]interface{})
 	if !ok {
-		return nil, fmt.Errorf("headers missing in resultSet")
+		return nil, apierror.New(apierror.Decode, "headers missing in resultSet")
 	}
 
 	headerKeys := make([]string, len(headers))
@@ -0,0 +1,267 @@
+package odds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sports_api/apierror"
+	"sports_api/globals/upstream"
+	"sports_api/logging"
+	"sync"
+	"time"
+)
+
+// KeyCooldown is how long an exhausted or unauthorized key is skipped before
+// it is tried again.
+var KeyCooldown = time.Hour
+
+// ErrNoUsableKey is returned when every key in the pool is exhausted or unauthorized.
+var ErrNoUsableKey = apierror.New(apierror.QuotaExhausted, "no usable odds API key")
+
+// Key states reported in KeyStatus.
+const (
+	KeyActive       = "active"
+	KeyExhausted    = "exhausted"    // The API reported no requests remaining
+	KeyUnauthorized = "unauthorized" // The API rejected the key with a 401
+)
+
+// KeyStatus is a snapshot of one key in a KeyPool. Only a masked form of the
+// key is included.
+type KeyStatus struct {
+	Key           string    `json:"key"` // Position and last four characters, e.g. "key1:****b993"
+	State         string    `json:"state"`
+	Remaining     int       `json:"remaining"` // As last reported by the API; -1 until known
+	Used          int       `json:"used"`      // As last reported by the API; -1 until known
+	Requests      int       `json:"requests"`
+	UpdatedAt     time.Time `json:"updatedAt,omitzero"`
+	DisabledUntil time.Time `json:"disabledUntil,omitzero"`
+}
+
+type apiKey struct {
+	secret string
+	status KeyStatus
+}
+
+// KeyPool rotates requests across API keys, skipping keys the API reports as
+// exhausted or unauthorized until KeyCooldown has passed.
+type KeyPool struct {
+	mu   sync.Mutex
+	keys []*apiKey
+	next int
+	now  func() time.Time
+}
+
+// NewKeyPool creates a KeyPool rotating across keys in order.
+func NewKeyPool(keys []string) *KeyPool {
+	p := &KeyPool{now: time.Now}
+	for i, key := range keys {
+		p.keys = append(p.keys, &apiKey{
+			secret: key,
+			status: KeyStatus{Key: fmt.Sprintf("key%d:%s", i+1, maskKey(key)), State: KeyActive, Remaining: -1, Used: -1},
+		})
+	}
+	return p
+}
+
+// Len returns the number of keys in the pool.
+func (p *KeyPool) Len() int {
+	return len(p.keys)
+}
+
+// Middleware sends each request with the next active key. When the API
+// reports that key exhausted or unauthorized, it is disabled and the request
+// is repeated with the next one. It belongs last in the chain so that neither
+// the circuit breaker nor retries see a rejected key. Without any keys,
+// requests are sent as they are.
+func (p *KeyPool) Middleware() upstream.Middleware {
+	return func(next upstream.Handler) upstream.Handler {
+		return func(ctx context.Context, req *upstream.Request) (*upstream.Response, error) {
+			if p.Len() == 0 {
+				return next(ctx, req)
+			}
+
+			var lastErr error
+			for range p.Len() {
+				key := p.pick()
+				if key == nil {
+					break
+				}
+				attempt := *req
+				attempt.Secrets = url.Values{"apiKey": {key.secret}}
+				resp, err := next(ctx, &attempt)
+				if !p.record(ctx, key, resp, err) {
+					return resp, err
+				}
+				lastErr = err
+			}
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w: %w", ErrNoUsableKey, lastErr)
+			}
+			return nil, ErrNoUsableKey
+		}
+	}
+}
+
+// pick returns the next active key in rotation, or nil if none is.
+func (p *KeyPool) pick() *apiKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reactivate()
+	for range p.keys {
+		key := p.keys[p.next]
+		p.next = (p.next + 1) % len(p.keys)
+		if key.status.State == KeyActive {
+			return key
+		}
+	}
+	return nil
+}
+
+// record updates key from the quota headers of a request made with it and
+// reports whether the request should be repeated with another key.
+func (p *KeyPool) record(ctx context.Context, key *apiKey, resp *upstream.Response, err error) bool {
+	var header http.Header
+	var statusErr *upstream.StatusError
+	switch {
+	case err == nil:
+		header = resp.Headers
+	case errors.As(err, &statusErr):
+		header = statusErr.Header
+	default:
+		return false // Network failures say nothing about the key
+	}
+	remaining, remainingOK := headerInt(header, "x-requests-remaining")
+	exhausted := remainingOK && remaining <= 0
+
+	p.mu.Lock()
+	status := &key.status
+	now := p.now()
+	status.Requests++
+	if used, ok := headerInt(header, "x-requests-used"); ok {
+		status.Used = used
+		status.UpdatedAt = now
+	}
+	if remainingOK {
+		status.Remaining = remaining
+		status.UpdatedAt = now
+	}
+
+	failover := false
+	state := KeyActive
+	switch {
+	case statusErr != nil && statusErr.StatusCode == http.StatusUnauthorized:
+		failover = true
+		state = KeyUnauthorized
+		if exhausted {
+			state = KeyExhausted
+		}
+	case statusErr != nil && statusErr.StatusCode == http.StatusTooManyRequests && exhausted:
+		failover = true
+		state = KeyExhausted
+	case exhausted:
+		// This request succeeded, but the next one made with the key would not
+		state = KeyExhausted
+	}
+	changed := state != status.State
+	if state != KeyActive {
+		status.State = state
+		status.DisabledUntil = now.Add(KeyCooldown)
+	}
+	masked := status.Key
+	p.mu.Unlock()
+
+	if changed {
+		logging.FromContext(ctx).WarnContext(ctx, "odds API key disabled", "key", masked, "state", state, "retry_after", KeyCooldown)
+	}
+	return failover
+}
+
+// reactivate returns keys whose cooldown has passed to the rotation, with
+// their remaining requests unknown until the API reports them again.
+func (p *KeyPool) reactivate() {
+	now := p.now()
+	for _, key := range p.keys {
+		if key.status.State != KeyActive && !now.Before(key.status.DisabledUntil) {
+			key.status.State = KeyActive
+			key.status.Remaining = -1
+			key.status.DisabledUntil = time.Time{}
+		}
+	}
+}
+
+// Remaining returns the requests left across the active keys, or -1 if the
+// API has not yet reported them for every active key or the pool is empty.
+func (p *KeyPool) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reactivate()
+	if len(p.keys) == 0 {
+		return -1
+	}
+	total := 0
+	for _, key := range p.keys {
+		if key.status.State != KeyActive {
+			continue
+		}
+		if key.status.Remaining < 0 {
+			return -1
+		}
+		total += key.status.Remaining
+	}
+	return total
+}
+
+// Used returns the requests used across every key this billing period, or -1
+// if the API has not reported any.
+func (p *KeyPool) Used() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := -1
+	for _, key := range p.keys {
+		if key.status.Used >= 0 {
+			total = max(total, 0) + key.status.Used
+		}
+	}
+	return total
+}
+
+// Active returns the number of keys currently in the rotation.
+func (p *KeyPool) Active() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reactivate()
+	active := 0
+	for _, key := range p.keys {
+		if key.status.State == KeyActive {
+			active++
+		}
+	}
+	return active
+}
+
+// Status returns a snapshot of every key, in rotation order.
+func (p *KeyPool) Status() []KeyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reactivate()
+	statuses := make([]KeyStatus, 0, len(p.keys))
+	for _, key := range p.keys {
+		statuses = append(statuses, key.status)
+	}
+	return statuses
+}
+
+// maskKey hides all but the last four characters of key, or all of a short key.
+func maskKey(key string) string {
+	if len(key) < 12 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
@@ -0,0 +1,104 @@
+package odds
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/logging"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyPoolRotatesAndFailsOver(t *testing.T) {
+	var mu sync.Mutex
+	remaining := map[string]int{"key-one-0001": 50, "key-two-0002": 1}
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := r.URL.Query().Get("apiKey")
+		sent = append(sent, key)
+		left, ok := remaining[key]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if left == 0 {
+			w.Header().Set("x-requests-remaining", "0")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		remaining[key] = left - 1
+		w.Header().Set("x-requests-remaining", strconv.Itoa(left-1))
+		w.Header().Set("x-requests-used", strconv.Itoa(100-left+1))
+		w.Header().Set("x-requests-last", "1")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Odds.BaseURL = server.URL
+	cfg.Odds.APIKey = "revoked-key-0000"
+	cfg.Odds.APIKeys = []string{"key-one-0001", "key-two-0002"}
+	cache := db.NewMemoryCache(0)
+	c := NewOddsApiClient(cfg, cache)
+	c.Limiter = nil
+	var logs bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	// The revoked key fails over to the first good one, then rotation moves on
+	for _, sport := range []string{"basketball_nba", "icehockey_nhl", "baseball_mlb"} {
+		if _, err := c.GetOddsRequest(ctx, c.GetSportEventsURL(sport), nil, nil); err != nil {
+			t.Fatalf("%s events returned error: %v", sport, err)
+		}
+	}
+	want := []string{"revoked-key-0000", "key-one-0001", "key-two-0002", "key-one-0001"}
+	if strings.Join(sent, ",") != strings.Join(want, ",") {
+		t.Errorf("keys sent = %q, want %q", sent, want)
+	}
+
+	states := make(map[string]string)
+	for _, status := range c.Keys.Status() {
+		states[status.Key] = status.State
+	}
+	if states["key1:****0000"] != KeyUnauthorized || states["key2:****0001"] != KeyActive || states["key3:****0002"] != KeyExhausted {
+		t.Errorf("key states = %v, want unauthorized, active and exhausted", states)
+	}
+	if usage := c.Quota.Usage(); usage.Remaining != 48 || len(usage.Keys) != 3 {
+		t.Errorf("Usage = %+v, want 48 remaining on the one active key", usage)
+	}
+
+	// Keys never reach cache keys or logs
+	if _, err := cache.Get(ctx, c.GetSportEventsURL("basketball_nba")); err != nil {
+		t.Errorf("response not cached under its URL without the key: %v", err)
+	}
+	for _, key := range append(cfg.Odds.Keys(), "apiKey") {
+		if strings.Contains(logs.String(), key) {
+			t.Errorf("log leaks %s: %s", key, logs.String())
+		}
+	}
+
+	// Once every key is disabled, requests fail without reaching the API
+	remaining["key-one-0001"] = 0
+	if _, err := c.GetOddsRequest(ctx, c.GetSportScoresURL("basketball_nba"), nil, nil); !errors.Is(err, ErrNoUsableKey) {
+		t.Errorf("request with the last key exhausted error = %v, want ErrNoUsableKey", err)
+	}
+	sent = nil
+	if _, err := c.GetOddsRequest(ctx, c.GetSportOddsURL("basketball_nba"), nil, nil); !errors.Is(err, ErrNoUsableKey) || len(sent) != 0 {
+		t.Errorf("request with no usable key = %v after sending %q, want ErrNoUsableKey without a request", err, sent)
+	}
+
+	// Keys rejoin the rotation after the cooldown
+	c.Keys.now = func() time.Time { return time.Now().Add(KeyCooldown) }
+	if active := c.Keys.Active(); active != 3 {
+		t.Errorf("Active after cooldown = %d, want 3", active)
+	}
+}
@@ -0,0 +1,78 @@
+package odds
+
+import (
+	"sports_api/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	quotaRemainingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "odds", "quota_remaining"),
+		"Request credits left on the Odds API account, as last reported by the API.",
+		nil, nil,
+	)
+	quotaUsedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "odds", "quota_used"),
+		"Request credits used this billing period, as last reported by the API.",
+		nil, nil,
+	)
+	creditsSpentDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "odds", "credits_spent"),
+		"Request credits this process has spent in the current UTC day or month.",
+		[]string{"period"}, nil,
+	)
+	keyActiveDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "odds", "key_active"),
+		"Whether an Odds API key is in the rotation (1) or skipped as exhausted or unauthorized (0).",
+		[]string{"key", "state"}, nil,
+	)
+	keyRemainingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "odds", "key_remaining"),
+		"Request credits left on an Odds API key, as last reported by the API.",
+		[]string{"key"}, nil,
+	)
+)
+
+// NewQuotaCollector returns a collector reporting c's quota and keys at
+// scrape time. Register it once, for the client requests are made with.
+func NewQuotaCollector(c *Client) prometheus.Collector {
+	return quotaCollector{c}
+}
+
+// quotaCollector reports a client's quota and keys at scrape time. The API's
+// own figures are omitted until a response has reported them. Keys are
+// labelled by their masked form.
+type quotaCollector struct {
+	client *Client
+}
+
+func (quotaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- quotaRemainingDesc
+	ch <- quotaUsedDesc
+	ch <- creditsSpentDesc
+	ch <- keyActiveDesc
+	ch <- keyRemainingDesc
+}
+
+func (q quotaCollector) Collect(ch chan<- prometheus.Metric) {
+	usage := q.client.Quota.Usage()
+	if usage.Remaining >= 0 {
+		ch <- prometheus.MustNewConstMetric(quotaRemainingDesc, prometheus.GaugeValue, float64(usage.Remaining))
+	}
+	if usage.Used >= 0 {
+		ch <- prometheus.MustNewConstMetric(quotaUsedDesc, prometheus.GaugeValue, float64(usage.Used))
+	}
+	ch <- prometheus.MustNewConstMetric(creditsSpentDesc, prometheus.GaugeValue, float64(usage.Today), "day")
+	ch <- prometheus.MustNewConstMetric(creditsSpentDesc, prometheus.GaugeValue, float64(usage.Month), "month")
+	for _, key := range usage.Keys {
+		active := 0.0
+		if key.State == KeyActive {
+			active = 1
+		}
+		ch <- prometheus.MustNewConstMetric(keyActiveDesc, prometheus.GaugeValue, active, key.Key, key.State)
+		if key.Remaining >= 0 {
+			ch <- prometheus.MustNewConstMetric(keyRemainingDesc, prometheus.GaugeValue, float64(key.Remaining), key.Key)
+		}
+	}
+}
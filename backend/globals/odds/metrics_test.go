@@ -0,0 +1,30 @@
+package odds
+
+import (
+	"net/http"
+	"sports_api/config"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestQuotaCollectorReportsItsClient(t *testing.T) {
+	cfg := config.Default()
+	cfg.Odds.APIKey = "test-key-0001"
+	c := NewOddsApiClient(cfg, nil)
+	c.Quota.Record("basketball_nba", []string{"h2h"}, http.Header{"X-Requests-Last": {"3"}})
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(NewQuotaCollector(c))
+	want := `
+# HELP sports_api_odds_credits_spent Request credits this process has spent in the current UTC day or month.
+# TYPE sports_api_odds_credits_spent gauge
+sports_api_odds_credits_spent{period="day"} 3
+sports_api_odds_credits_spent{period="month"} 3
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "sports_api_odds_credits_spent"); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,20 +1,20 @@
 package odds
 
 import (
+	"context"
 	"fmt"
+	"sports_api/internal/testutil"
 	"testing"
 )
 
 func TestGetSports(t *testing.T) {
-	// Replace with your actual API key
-	apiKey := "6712797a115aa05245a64e00077eb993"
-	client := NewOddsApiClient(apiKey)
+	client := NewOddsApiClient(testutil.FakeOddsAPI(t), nil)
 
 	// Call the /v4/sports endpoint without appending anything
 	// No additional sport appended
 	// No extra query params
 
-	response, err := client.GetOddsRequest(client.GetSportsURL(), nil, nil)
+	response, err := client.GetOddsRequest(context.Background(), client.GetSportsURL(), nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to fetch sports: %v", err)
 	}
@@ -0,0 +1,332 @@
+package odds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sports_api/apierror"
+	"sports_api/globals/upstream"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned for non-critical requests that would spend
+// past the daily or monthly budget.
+var ErrBudgetExceeded = apierror.New(apierror.QuotaExhausted, "odds request budget exceeded")
+
+// Budget caps the request credits non-critical fetches may spend. Zero is unlimited.
+type Budget struct {
+	Daily   int `json:"daily"`
+	Monthly int `json:"monthly"`
+}
+
+// MarketUsage is the credits spent on one sport and market.
+type MarketUsage struct {
+	Sport    string `json:"sport"`
+	Market   string `json:"market"`
+	Requests int    `json:"requests"`
+	Today    int    `json:"today"`
+	Month    int    `json:"month"`
+}
+
+// QuotaUsage is a snapshot of the quota across the API keys and the credits spent by this process.
+type QuotaUsage struct {
+	Remaining int           `json:"remaining"` // Across the active keys, as last reported by the API; -1 until known
+	Used      int           `json:"used"`      // Across every key, as last reported by the API; -1 until known
+	UpdatedAt time.Time     `json:"updatedAt,omitzero"`
+	Budget    Budget        `json:"budget"`
+	Today     int           `json:"today"` // Credits spent in the current UTC day
+	Month     int           `json:"month"` // Credits spent in the current UTC month
+	Markets   []MarketUsage `json:"markets"`
+	Keys      []KeyStatus   `json:"keys"`
+}
+
+type marketKey struct{ sport, market string }
+
+// QuotaTracker records the credits each response from The Odds API reports
+// it cost and holds non-critical fetches to the budget. The account figures
+// come from the keys in its KeyPool.
+type QuotaTracker struct {
+	Budget Budget
+
+	keys      *KeyPool
+	mu        sync.Mutex
+	updatedAt time.Time
+	day       string
+	month     string
+	today     int
+	thisMonth int
+	reserved  int // Estimated credits of requests still in flight
+	markets   map[marketKey]*MarketUsage
+	now       func() time.Time
+}
+
+// NewQuotaTracker creates a QuotaTracker enforcing budget across keys.
+func NewQuotaTracker(budget Budget, keys *KeyPool) *QuotaTracker {
+	return &QuotaTracker{
+		Budget:  budget,
+		keys:    keys,
+		markets: make(map[marketKey]*MarketUsage),
+		now:     time.Now,
+	}
+}
+
+type nonCriticalKey struct{}
+
+// NonCritical marks requests made with ctx as optional, so they are refused
+// rather than spend past the budget.
+func NonCritical(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonCriticalKey{}, true)
+}
+
+func isNonCritical(ctx context.Context) bool {
+	optional, _ := ctx.Value(nonCriticalKey{}).(bool)
+	return optional
+}
+
+// Middleware refuses non-critical requests over budget and records the quota
+// headers of every response. Each billed request reserves its estimated cost
+// before it is sent, so concurrent requests cannot all pass the budget check
+// and overshoot it together; the reservation is replaced by the cost the
+// response reports. It belongs after the cache so hits cost nothing.
+func (q *QuotaTracker) Middleware() upstream.Middleware {
+	return func(next upstream.Handler) upstream.Handler {
+		return func(ctx context.Context, req *upstream.Request) (*upstream.Response, error) {
+			sport, markets, cost := billing(req)
+			if err := q.reserve(cost, !isNonCritical(ctx)); err != nil {
+				return nil, err
+			}
+
+			resp, err := next(ctx, req)
+			var header http.Header
+			var statusErr *upstream.StatusError
+			switch {
+			case err == nil:
+				header = resp.Headers
+			case errors.As(err, &statusErr):
+				header = statusErr.Header
+			}
+			q.settle(cost, sport, markets, header)
+			return resp, err
+		}
+	}
+}
+
+// Allow reports whether cost credits fit in the remaining budget, counting
+// the credits reserved by requests in flight as spent.
+func (q *QuotaTracker) Allow(cost int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.allow(cost)
+}
+
+func (q *QuotaTracker) allow(cost int) error {
+	q.roll()
+	if remaining := q.keys.Remaining(); remaining >= 0 && q.reserved+cost > remaining {
+		return fmt.Errorf("%w: %d credits left across the API keys with %d reserved, request needs %d", ErrBudgetExceeded, remaining, q.reserved, cost)
+	}
+	if q.Budget.Daily > 0 && q.today+q.reserved+cost > q.Budget.Daily {
+		return fmt.Errorf("%w: %d of %d daily credits used and %d reserved, request needs %d", ErrBudgetExceeded, q.today, q.Budget.Daily, q.reserved, cost)
+	}
+	// The API's own count also covers other processes sharing the keys
+	monthly := max(q.thisMonth, q.keys.Used())
+	if q.Budget.Monthly > 0 && monthly+q.reserved+cost > q.Budget.Monthly {
+		return fmt.Errorf("%w: %d of %d monthly credits used and %d reserved, request needs %d", ErrBudgetExceeded, monthly, q.Budget.Monthly, q.reserved, cost)
+	}
+	return nil
+}
+
+// reserve holds cost credits for a request about to be sent. Critical
+// requests always get their reservation; others are refused if it would not
+// fit in the budget.
+func (q *QuotaTracker) reserve(cost int, critical bool) error {
+	if cost <= 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !critical {
+		if err := q.allow(cost); err != nil {
+			return err
+		}
+	}
+	q.reserved += cost
+	return nil
+}
+
+// settle releases a request's reservation and records the cost its response
+// headers report, in one step so no other request sees neither.
+func (q *QuotaTracker) settle(reserved int, sport string, markets []string, header http.Header) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reserved -= max(reserved, 0)
+	if header != nil {
+		q.record(sport, markets, header)
+	}
+}
+
+// Fit returns the leading markets that can be requested across regions
+// within the budget, or nil if not even one fits.
+func (q *QuotaTracker) Fit(markets []string, regions int) []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for n := len(markets); n > 0; n-- {
+		if q.allow(n*regions) == nil {
+			return markets[:n]
+		}
+	}
+	return nil
+}
+
+// Record attributes the credits a response's headers report it cost evenly
+// across markets. The KeyPool records the account figures in the same headers.
+func (q *QuotaTracker) Record(sport string, markets []string, header http.Header) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.record(sport, markets, header)
+}
+
+func (q *QuotaTracker) record(sport string, markets []string, header http.Header) {
+	cost, ok := headerInt(header, "x-requests-last")
+	if !ok {
+		return
+	}
+	q.updatedAt = q.now()
+
+	q.roll()
+	q.today += cost
+	q.thisMonth += cost
+	for i, market := range markets {
+		share := cost / len(markets)
+		if i < cost%len(markets) {
+			share++
+		}
+		usage := q.market(sport, market)
+		usage.Requests++
+		usage.Today += share
+		usage.Month += share
+	}
+}
+
+// Usage returns a snapshot of the quota, with markets sorted by sport and market.
+func (q *QuotaTracker) Usage() QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll()
+	usage := QuotaUsage{
+		Remaining: q.keys.Remaining(),
+		Used:      q.keys.Used(),
+		UpdatedAt: q.updatedAt,
+		Budget:    q.Budget,
+		Today:     q.today,
+		Month:     q.thisMonth,
+		Markets:   make([]MarketUsage, 0, len(q.markets)),
+		Keys:      q.keys.Status(),
+	}
+	for _, market := range q.markets {
+		usage.Markets = append(usage.Markets, *market)
+	}
+	sort.Slice(usage.Markets, func(i, j int) bool {
+		a, b := usage.Markets[i], usage.Markets[j]
+		if a.Sport != b.Sport {
+			return a.Sport < b.Sport
+		}
+		return a.Market < b.Market
+	})
+	return usage
+}
+
+func (q *QuotaTracker) market(sport, market string) *MarketUsage {
+	key := marketKey{sport, market}
+	usage, ok := q.markets[key]
+	if !ok {
+		usage = &MarketUsage{Sport: sport, Market: market}
+		q.markets[key] = usage
+	}
+	return usage
+}
+
+// roll resets the daily and monthly counters when a new UTC period starts.
+func (q *QuotaTracker) roll() {
+	now := q.now().UTC()
+	if day := now.Format("2006-01-02"); day != q.day {
+		q.day = day
+		q.today = 0
+		for _, usage := range q.markets {
+			usage.Today = 0
+		}
+	}
+	if month := now.Format("2006-01"); month != q.month {
+		q.month = month
+		q.thisMonth = 0
+		for _, usage := range q.markets {
+			usage.Month = 0
+		}
+	}
+}
+
+// billing returns the sport and markets a request is billed for and its
+// estimated cost: one credit per market per region for odds, one for scores
+// (two with daysFrom), and nothing for sports and events listings.
+func billing(req *upstream.Request) (sport string, markets []string, cost int) {
+	parts := strings.Split(strings.Trim(req.Endpoint, "/"), "/")
+	sport = parts[0]
+	var query url.Values
+	if u, err := url.Parse(req.URL); err == nil {
+		query = u.Query()
+	}
+
+	switch last := parts[len(parts)-1]; {
+	case sport == "":
+		return "", []string{"sports"}, 0
+	case last == "odds":
+		markets = splitParam(query.Get("markets"))
+		if len(markets) == 0 {
+			markets = []string{"h2h"} // The API's default market
+		}
+		return sport, markets, len(markets) * regionCount(query.Get("regions"))
+	case last == "scores":
+		if query.Get("daysFrom") != "" {
+			return sport, []string{"scores"}, 2
+		}
+		return sport, []string{"scores"}, 1
+	default:
+		return sport, []string{last}, 0
+	}
+}
+
+// regionCount returns the number of regions in a regions parameter, at least one.
+func regionCount(regions string) int {
+	return max(len(splitParam(regions)), 1)
+}
+
+// splitParam splits a comma separated query parameter, dropping blank entries.
+func splitParam(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func headerInt(header http.Header, name string) (int, bool) {
+	value := header.Get(name)
+	if value == "" {
+		return 0, false
+	}
+	// The API reports fractional usage for some plans
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(n), true
+}
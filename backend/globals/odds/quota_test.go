@@ -0,0 +1,191 @@
+package odds
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/globals/upstream"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQuotaTrackerRecordsAndEnforcesBudget(t *testing.T) {
+	var mu sync.Mutex
+	used := 100
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Query().Get("apiKey") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		cost := 0
+		if strings.HasSuffix(r.URL.Path, "/odds") {
+			cost = len(strings.Split(r.URL.Query().Get("markets"), ","))
+			requested = append(requested, r.URL.Query().Get("markets"))
+		}
+		used += cost
+		w.Header().Set("x-requests-used", strconv.Itoa(used))
+		w.Header().Set("x-requests-remaining", strconv.Itoa(500-used))
+		w.Header().Set("x-requests-last", strconv.Itoa(cost))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Odds.BaseURL = server.URL
+	cfg.Odds.APIKey = "test-key"
+	cfg.Odds.DailyBudget = 5
+	c := NewOddsApiClient(cfg, nil)
+	ctx := context.Background()
+
+	if _, err := c.GetOddsRequest(ctx, c.GetSportEventsURL("basketball_nba"), nil, nil); err != nil {
+		t.Fatalf("events request returned error: %v", err)
+	}
+	props := map[string]string{"regions": "us", "markets": "player_points,player_rebounds,player_assists"}
+	if _, err := c.GetOptionalOddsRequest(ctx, c.GetEventOddsURL("basketball_nba", "a"), props, nil); err != nil {
+		t.Fatalf("first props request returned error: %v", err)
+	}
+
+	// Only two of the three markets still fit in the daily budget
+	if _, err := c.GetOptionalOddsRequest(ctx, c.GetEventOddsURL("basketball_nba", "b"), props, nil); err != nil {
+		t.Fatalf("downgraded props request returned error: %v", err)
+	}
+	if len(requested) != 2 || requested[1] != "player_points,player_rebounds" {
+		t.Errorf("requested markets = %q, want the second request downgraded to two markets", requested)
+	}
+
+	// Nothing fits now, so non-critical requests are refused but critical ones still go out
+	if _, err := c.GetOptionalOddsRequest(ctx, c.GetEventOddsURL("basketball_nba", "c"), props, nil); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("props request over budget error = %v, want ErrBudgetExceeded", err)
+	}
+	if _, err := c.GetOddsRequest(ctx, c.GetSportOddsURL("basketball_nba"), map[string]string{"markets": "h2h"}, nil); err != nil {
+		t.Errorf("critical request returned error: %v", err)
+	}
+
+	usage := c.Quota.Usage()
+	if usage.Used != 106 || usage.Remaining != 394 || usage.Today != 6 {
+		t.Errorf("Usage = %+v, want used 106, remaining 394 and 6 credits today", usage)
+	}
+	points := findMarket(usage.Markets, "basketball_nba", "player_points")
+	if points == nil || points.Requests != 2 || points.Today != 2 {
+		t.Errorf("player_points usage = %+v, want 2 requests costing 2", points)
+	}
+	if assists := findMarket(usage.Markets, "basketball_nba", "player_assists"); assists == nil || assists.Today != 1 {
+		t.Errorf("player_assists usage = %+v, want 1 credit", assists)
+	}
+	if events := findMarket(usage.Markets, "basketball_nba", "events"); events == nil || events.Today != 0 {
+		t.Errorf("events usage = %+v, want a free request", events)
+	}
+}
+
+func TestQuotaMiddlewareReservesConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent++
+		mu.Unlock()
+		// Keep every request in flight long enough for the others to be checked
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("x-requests-last", "1")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Odds.BaseURL = server.URL
+	cfg.Odds.APIKey = "test-key"
+	cfg.Odds.DailyBudget = 3
+	c := NewOddsApiClient(cfg, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			params := map[string]string{"regions": "us", "markets": "h2h"}
+			_, _ = c.GetOptionalOddsRequest(context.Background(), c.GetEventOddsURL("basketball_nba", strconv.Itoa(i)), params, nil)
+		}()
+	}
+	wg.Wait()
+
+	if sent > 3 {
+		t.Errorf("sent %d requests, want at most the 3 the daily budget allows", sent)
+	}
+	if usage := c.Quota.Usage(); usage.Today != sent {
+		t.Errorf("Today = %d, want the %d credits the responses reported", usage.Today, sent)
+	}
+	if c.Quota.reserved != 0 {
+		t.Errorf("reserved = %d after every request finished, want 0", c.Quota.reserved)
+	}
+}
+
+func TestQuotaMiddlewareSitsBetweenCacheAndBreaker(t *testing.T) {
+	var mu sync.Mutex
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent++
+		w.Header().Set("x-requests-last", "1")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Odds.BaseURL = server.URL
+	cfg.Odds.APIKey = "test-key"
+	cfg.Odds.DailyBudget = 1
+	cache := db.NewMemoryCache(0)
+	c := NewOddsApiClient(cfg, cache)
+	ctx := context.Background()
+	params := map[string]string{"regions": "us", "markets": "h2h"}
+
+	// The second request is a cache hit, so it is served although the budget is spent
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetOptionalOddsRequest(ctx, c.GetEventOddsURL("basketball_nba", "a"), params, nil); err != nil {
+			t.Fatalf("request %d returned error: %v", i+1, err)
+		}
+	}
+	if sent != 1 || c.Quota.Usage().Today != 1 {
+		t.Errorf("sent %d requests costing %d, want the cache hit to cost nothing", sent, c.Quota.Usage().Today)
+	}
+
+	// Refusals never reach the circuit breaker, however many there are
+	for i := 0; i <= upstream.DefaultBreakerSettings.FailureThreshold; i++ {
+		if _, err := c.GetOptionalOddsRequest(ctx, c.GetEventOddsURL("basketball_nba", strconv.Itoa(i)), params, nil); !errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("request over budget error = %v, want ErrBudgetExceeded", err)
+		}
+	}
+	if state := upstream.DefaultBreaker.State(strings.TrimPrefix(server.URL, "http://")); state != upstream.CircuitClosed {
+		t.Errorf("circuit state after budget refusals = %s, want closed", state)
+	}
+
+	// The key is only added after the cache, so it never appears in cache keys
+	keys, _ := cache.Keys(ctx, "")
+	for _, key := range keys {
+		if strings.Contains(key, "test-key") {
+			t.Errorf("cache key %q contains the API key", key)
+		}
+	}
+	if len(keys) == 0 {
+		t.Error("nothing was cached")
+	}
+}
+
+func findMarket(markets []MarketUsage, sport, market string) *MarketUsage {
+	for i := range markets {
+		if markets[i].Sport == sport && markets[i].Market == market {
+			return &markets[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Package providers builds the upstream clients the API fetches through.
+// They are built once from the configuration at startup and handed to the
+// routes, so nothing reaches an upstream through package state.
+package providers
+
+import (
+	"log/slog"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/globals/mlb"
+	"sports_api/globals/nba"
+	"sports_api/globals/nhl"
+	"sports_api/globals/odds"
+	"sports_api/globals/upstream"
+)
+
+// Clients holds one configured client per provider.
+type Clients struct {
+	NBA  *nba.Client
+	MLB  *mlb.Client
+	NHL  *nhl.Client
+	Odds *odds.Client
+}
+
+// New builds every provider's client from cfg. A nil cache disables response caching.
+func New(cfg *config.Config, cache db.Cache) *Clients {
+	if len(cfg.Odds.Keys()) == 0 {
+		slog.Warn("odds API key is not set")
+	}
+	return &Clients{
+		NBA:  nba.NewNBAClient(cfg, cache),
+		MLB:  mlb.NewMLBClient(cfg, cache),
+		NHL:  nhl.NewNHLClient(cfg, cache),
+		Odds: odds.NewOddsApiClient(cfg, cache),
+	}
+}
+
+// Upstream is the upstream clients of one provider.
+type Upstream struct {
+	Name    string // Lower case, e.g. nba
+	Clients []*upstream.Client
+}
+
+// Upstreams returns the upstream clients grouped by provider, in the order
+// nba, mlb, nhl, odds.
+func (c *Clients) Upstreams() []Upstream {
+	return []Upstream{
+		{"nba", []*upstream.Client{c.NBA.Client}},
+		{"mlb", []*upstream.Client{c.MLB.Client}},
+		{"nhl", []*upstream.Client{c.NHL.Stats, c.NHL.Web}},
+		{"odds", []*upstream.Client{c.Odds.Client}},
+	}
+}
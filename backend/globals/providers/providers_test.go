@@ -0,0 +1,24 @@
+package providers
+
+import (
+	"sports_api/config"
+	"testing"
+	"time"
+)
+
+func TestNewAppliesTTLOverridesToEveryClient(t *testing.T) {
+	cfg := config.Default()
+	cfg.Cache.TTLOverrides = map[string]time.Duration{"*": time.Minute}
+	clients := New(cfg, nil)
+
+	for _, u := range clients.Upstreams() {
+		for _, client := range u.Clients {
+			if got := client.CacheTTLFor("anything"); got != time.Minute {
+				t.Errorf("%s client %s TTL = %s, want the configured override", u.Name, client.BaseURL, got)
+			}
+		}
+	}
+	if other := New(config.Default(), nil); other.NBA.CacheTTLFor("anything") == time.Minute {
+		t.Error("overrides leaked into a client built from another configuration")
+	}
+}
@@ -0,0 +1,126 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sports_api/db"
+	"strings"
+	"time"
+)
+
+// CachedEntry describes one cached upstream response.
+type CachedEntry struct {
+	Key      string        `json:"key"`
+	Provider string        `json:"provider"`
+	Endpoint string        `json:"endpoint"`
+	StoredAt time.Time     `json:"storedAt"`
+	Age      time.Duration `json:"age"`
+	FreshFor time.Duration `json:"freshFor"` // Time left before the soft expiry; negative once stale
+	TTL      time.Duration `json:"ttl"`      // Time left before the store drops the entry
+}
+
+// endpointOf returns the endpoint of a cache key, which is the request URL
+// relative to the client's BaseURL without its query string.
+func (c *Client) endpointOf(key string) string {
+	endpoint, _, _ := strings.Cut(strings.TrimPrefix(key, c.BaseURL), "?")
+	return endpoint
+}
+
+// cachedKeys lists the client's cache keys, limited to endpoint when it is not empty.
+func (c *Client) cachedKeys(ctx context.Context, endpoint string) ([]string, error) {
+	if c.Cache == nil {
+		return nil, nil
+	}
+	keys, err := c.Cache.Keys(ctx, c.BaseURL+endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s cache keys: %w", c.Name, err)
+	}
+	if endpoint == "" {
+		return keys, nil
+	}
+
+	// The prefix for "playergamelog" also matches "playergamelogs"
+	matched := keys[:0]
+	for _, key := range keys {
+		if c.endpointOf(key) == endpoint {
+			matched = append(matched, key)
+		}
+	}
+	return matched, nil
+}
+
+// CachedEntries lists the client's cached responses for endpoint, or for every
+// endpoint when it is empty, sorted by key.
+func (c *Client) CachedEntries(ctx context.Context, endpoint string) ([]CachedEntry, error) {
+	keys, err := c.cachedKeys(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entries := make([]CachedEntry, 0, len(keys))
+	for _, key := range keys {
+		data, err := c.Cache.Get(ctx, key)
+		if errors.Is(err, db.ErrCacheMiss) {
+			continue // Expired since it was listed
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading cache entry %s: %w", key, err)
+		}
+		meta, _, err := decodeCacheEntry(data)
+		if err != nil {
+			continue
+		}
+		ttl, err := c.Cache.TTL(ctx, key)
+		if err != nil && !errors.Is(err, db.ErrCacheMiss) {
+			return nil, fmt.Errorf("error reading cache TTL %s: %w", key, err)
+		}
+
+		entries = append(entries, CachedEntry{
+			Key:      key,
+			Provider: c.Name,
+			Endpoint: c.endpointOf(key),
+			StoredAt: meta.StoredAt,
+			Age:      now.Sub(meta.StoredAt),
+			FreshFor: meta.SoftExpiry.Sub(now),
+			TTL:      ttl,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// InvalidateEndpoint deletes every cached response for endpoint and returns how many were removed.
+func (c *Client) InvalidateEndpoint(ctx context.Context, endpoint string) (int, error) {
+	if endpoint == "" {
+		return 0, errors.New("endpoint is required")
+	}
+	keys, err := c.cachedKeys(ctx, endpoint)
+	if err != nil {
+		return 0, err
+	}
+	return deleteKeys(ctx, c.Cache, keys)
+}
+
+// InvalidatePrefix deletes every cached response whose URL starts with prefix.
+func InvalidatePrefix(ctx context.Context, store db.Cache, prefix string) (int, error) {
+	if prefix == "" {
+		return 0, errors.New("prefix is required")
+	}
+	keys, err := store.Keys(ctx, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("error listing cache keys: %w", err)
+	}
+	return deleteKeys(ctx, store, keys)
+}
+
+func deleteKeys(ctx context.Context, store db.Cache, keys []string) (int, error) {
+	for i, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			return i, fmt.Errorf("error deleting cache key %s: %w", key, err)
+		}
+	}
+	return len(keys), nil
+}
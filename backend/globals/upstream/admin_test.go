@@ -0,0 +1,71 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/db"
+	"testing"
+	"time"
+)
+
+func TestCacheAdmin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	store := db.NewMemoryCache(0)
+	c := newTestClient(server.URL + "/")
+	c.Cache = store
+	c.Use(Cache(store, func(string) CachePolicy { return CachePolicy{TTL: time.Hour} }))
+
+	ctx := context.Background()
+	for _, call := range []struct {
+		endpoint string
+		params   map[string]string
+	}{
+		{"playergamelogs", map[string]string{"Season": "2024-25"}},
+		{"playergamelogs", map[string]string{"Season": "2023-24"}},
+		{"playergamelog", map[string]string{"PlayerID": "2544"}},
+		{"scoreboardv2", nil},
+	} {
+		if _, err := c.Get(ctx, call.endpoint, call.params, "", nil); err != nil {
+			t.Fatalf("Get(%s) returned error: %v", call.endpoint, err)
+		}
+	}
+
+	entries, err := c.CachedEntries(ctx, "playergamelogs")
+	if err != nil {
+		t.Fatalf("CachedEntries returned error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("CachedEntries(playergamelogs) = %+v, want 2 entries", entries)
+	}
+	for _, entry := range entries {
+		if entry.Endpoint != "playergamelogs" || entry.Provider != "Test" {
+			t.Errorf("entry = %+v, want Test playergamelogs", entry)
+		}
+		if entry.FreshFor <= 59*time.Minute || entry.TTL <= entry.FreshFor || entry.Age < 0 {
+			t.Errorf("entry = %+v, want fresh for about 1h and kept for longer", entry)
+		}
+	}
+	if all, err := c.CachedEntries(ctx, ""); err != nil || len(all) != 4 {
+		t.Errorf("CachedEntries(\"\") = %d entries, %v, want 4", len(all), err)
+	}
+
+	// Invalidating playergamelogs must leave playergamelog alone
+	if n, err := c.InvalidateEndpoint(ctx, "playergamelogs"); err != nil || n != 2 {
+		t.Errorf("InvalidateEndpoint = %d, %v, want 2", n, err)
+	}
+	if entries, _ := c.CachedEntries(ctx, "playergamelog"); len(entries) != 1 {
+		t.Errorf("CachedEntries(playergamelog) = %+v, want 1 entry", entries)
+	}
+
+	if n, err := InvalidatePrefix(ctx, store, server.URL+"/scoreboard"); err != nil || n != 1 {
+		t.Errorf("InvalidatePrefix = %d, %v, want 1", n, err)
+	}
+	if all, _ := c.CachedEntries(ctx, ""); len(all) != 1 {
+		t.Errorf("CachedEntries after invalidation = %+v, want only playergamelog", all)
+	}
+}
@@ -0,0 +1,227 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"sports_api/apierror"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the upstream while its host's circuit is open.
+var ErrCircuitOpen = apierror.New(apierror.Unavailable, "circuit open")
+
+// CircuitState is the state of a single host's circuit.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Requests flow normally
+	CircuitOpen                         // Requests fail fast until the cooldown elapses
+	CircuitHalfOpen                     // A single probe request decides whether to close again
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// BreakerSettings controls when a host's circuit trips and how long it stays open.
+type BreakerSettings struct {
+	FailureThreshold int           // Consecutive failures that trip the circuit
+	Cooldown         time.Duration // Time spent open before a probe is let through
+}
+
+// DefaultBreakerSettings trips after five consecutive failures and probes again after 30 seconds.
+var DefaultBreakerSettings = BreakerSettings{
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+}
+
+// DefaultBreaker is shared by every client so a blocked host fails fast for all sessions.
+var DefaultBreaker = NewBreaker(DefaultBreakerSettings)
+
+// Breaker tracks a circuit per upstream host.
+type Breaker struct {
+	Settings BreakerSettings
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+	now   func() time.Time
+}
+
+type circuit struct {
+	state      CircuitState
+	failures   int
+	openedAt   time.Time
+	probing    bool
+	trips      int64
+	recoveries int64
+}
+
+// BreakerStats is a snapshot of one host's circuit.
+type BreakerStats struct {
+	Host       string
+	State      CircuitState
+	Failures   int   // Consecutive failures while closed
+	Trips      int64 // Times the circuit has opened
+	Recoveries int64 // Times a probe has closed the circuit again
+}
+
+// NewBreaker creates a Breaker applying settings to every host.
+func NewBreaker(settings BreakerSettings) *Breaker {
+	return &Breaker{
+		Settings: settings,
+		hosts:    make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+// CircuitBreaker fails requests fast with ErrCircuitOpen while the target host's circuit is open.
+// It should sit outside Retry so one logical call counts as a single success or failure.
+func CircuitBreaker(b *Breaker) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			u, err := url.Parse(req.URL)
+			if err != nil {
+				return next(ctx, req)
+			}
+			host := u.Host
+
+			if err := b.Allow(host); err != nil {
+				return nil, fmt.Errorf("%s: %w", host, err)
+			}
+			resp, err := next(ctx, req)
+			b.Record(host, err)
+			return resp, err
+		}
+	}
+}
+
+// Allow reports whether a request to host may proceed, moving an open circuit
+// to half-open once its cooldown has elapsed.
+func (b *Breaker) Allow(host string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	switch c.state {
+	case CircuitOpen:
+		if b.now().Sub(c.openedAt) < b.Settings.Cooldown {
+			return ErrCircuitOpen
+		}
+		c.state = CircuitHalfOpen
+		c.probing = true
+		slog.Info("circuit half-open, sending probe request", "host", host)
+	case CircuitHalfOpen:
+		if c.probing {
+			return ErrCircuitOpen
+		}
+		c.probing = true
+	}
+	return nil
+}
+
+// Record updates host's circuit with the outcome of a request that Allow let through.
+func (b *Breaker) Record(host string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	switch outcome(err) {
+	case outcomeNeutral:
+		c.probing = false
+	case outcomeSuccess:
+		if c.state == CircuitHalfOpen {
+			c.recoveries++
+			slog.Info("circuit closed, upstream recovered", "host", host)
+		}
+		c.state = CircuitClosed
+		c.failures = 0
+		c.probing = false
+	case outcomeFailure:
+		c.failures++
+		if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= b.Settings.FailureThreshold) {
+			c.state = CircuitOpen
+			c.openedAt = b.now()
+			c.probing = false
+			c.trips++
+			slog.Warn("circuit opened", "host", host, "failures", c.failures, "error", err)
+		}
+	}
+}
+
+// State returns the current state of host's circuit.
+func (b *Breaker) State(host string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.circuit(host).state
+}
+
+// Stats returns a snapshot of every host's circuit, sorted by host.
+func (b *Breaker) Stats() []BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := make([]BreakerStats, 0, len(b.hosts))
+	for host, c := range b.hosts {
+		stats = append(stats, BreakerStats{
+			Host:       host,
+			State:      c.state,
+			Failures:   c.failures,
+			Trips:      c.trips,
+			Recoveries: c.recoveries,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Host < stats[j].Host })
+	return stats
+}
+
+func (b *Breaker) circuit(host string) *circuit {
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &circuit{}
+		b.hosts[host] = c
+	}
+	return c
+}
+
+type requestOutcome int
+
+const (
+	outcomeSuccess requestOutcome = iota
+	outcomeFailure
+	outcomeNeutral
+)
+
+// outcome decides whether err says anything about the upstream's health.
+// Client errors such as 404 show the host is up; a caller cancelling says nothing.
+func outcome(err error) requestOutcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+	if errors.Is(err, context.Canceled) {
+		return outcomeNeutral
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode >= 500,
+			statusErr.StatusCode == http.StatusTooManyRequests,
+			statusErr.StatusCode == http.StatusForbidden:
+			return outcomeFailure
+		}
+		return outcomeSuccess
+	}
+	return outcomeFailure
+}
@@ -0,0 +1,174 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/apierror"
+	"sports_api/db"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerTripsAndRecovers(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewBreaker(BreakerSettings{FailureThreshold: 2, Cooldown: time.Minute})
+	b.now = func() time.Time { return now }
+
+	unavailable := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	for i := 0; i < 2; i++ {
+		if err := b.Allow("stats.nba.com"); err != nil {
+			t.Fatalf("Allow on closed circuit returned %v", err)
+		}
+		b.Record("stats.nba.com", unavailable)
+	}
+	if state := b.State("stats.nba.com"); state != CircuitOpen {
+		t.Fatalf("state after 2 failures = %s, want open", state)
+	}
+	if err := b.Allow("stats.nba.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow on open circuit = %v, want ErrCircuitOpen", err)
+	}
+	if err := b.Allow("statsapi.mlb.com"); err != nil {
+		t.Errorf("Allow for another host = %v, want nil", err)
+	}
+
+	// After the cooldown a single probe is let through
+	now = now.Add(time.Minute)
+	if err := b.Allow("stats.nba.com"); err != nil {
+		t.Fatalf("Allow after cooldown = %v, want probe to be let through", err)
+	}
+	if err := b.Allow("stats.nba.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second Allow while probing = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed probe re-opens the circuit, a successful one closes it
+	b.Record("stats.nba.com", unavailable)
+	if state := b.State("stats.nba.com"); state != CircuitOpen {
+		t.Fatalf("state after failed probe = %s, want open", state)
+	}
+	now = now.Add(time.Minute)
+	_ = b.Allow("stats.nba.com")
+	b.Record("stats.nba.com", nil)
+	if state := b.State("stats.nba.com"); state != CircuitClosed {
+		t.Fatalf("state after successful probe = %s, want closed", state)
+	}
+
+	stats := b.Stats()
+	if len(stats) != 2 || stats[0].Host != "stats.nba.com" || stats[0].Trips != 2 || stats[0].Recoveries != 1 {
+		t.Errorf("Stats = %+v, want 2 trips and 1 recovery for stats.nba.com", stats)
+	}
+}
+
+func TestBreakerIgnoresClientErrors(t *testing.T) {
+	b := NewBreaker(BreakerSettings{FailureThreshold: 1, Cooldown: time.Minute})
+
+	b.Record("api-web.nhle.com", &StatusError{StatusCode: http.StatusNotFound})
+	b.Record("api-web.nhle.com", context.Canceled)
+	if state := b.State("api-web.nhle.com"); state != CircuitClosed {
+		t.Errorf("state after 404 and cancellation = %s, want closed", state)
+	}
+
+	b.Record("api-web.nhle.com", &StatusError{StatusCode: http.StatusForbidden})
+	if state := b.State("api-web.nhle.com"); state != CircuitOpen {
+		t.Errorf("state after 403 = %s, want open", state)
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL + "/")
+	c.Use(CircuitBreaker(NewBreaker(BreakerSettings{FailureThreshold: 3, Cooldown: time.Minute})))
+
+	for i := 0; i < 5; i++ {
+		_, err := c.Get(context.Background(), "endpoint", nil, "", nil)
+		if i >= 3 && !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Get %d error = %v, want ErrCircuitOpen", i+1, err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 before the circuit opened", calls)
+	}
+}
+
+func TestCacheServesStaleWhenUpstreamUnavailable(t *testing.T) {
+	const blockPage = -1 // A 200 carrying an HTML error page
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch code := int(status.Load()); code {
+		case http.StatusOK:
+			_, _ = w.Write([]byte(`{"resource": "commonallplayers"}`))
+		case http.StatusGatewayTimeout:
+			time.Sleep(100 * time.Millisecond)
+		case blockPage:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html>Access Denied</html>"))
+		default:
+			w.WriteHeader(code)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL + "/")
+	c.Use(Cache(db.NewMemoryCache(0), func(string) CachePolicy { return CachePolicy{TTL: time.Nanosecond} }), CircuitBreaker(NewBreaker(BreakerSettings{FailureThreshold: 3, Cooldown: time.Minute})))
+	if _, err := c.Get(context.Background(), "commonallplayers", nil, "", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	// A 5xx, a 429, a 403, a block page and a timeout each fall back on the expired entry,
+	// and the failures trip the circuit for the last case
+	for _, test := range []struct {
+		name   string
+		status int
+	}{
+		{"server error", http.StatusInternalServerError},
+		{"rate limited", http.StatusTooManyRequests},
+		{"blocked", http.StatusForbidden},
+		{"HTML block page", blockPage},
+		{"timeout", http.StatusGatewayTimeout},
+		{"circuit open", http.StatusOK},
+	} {
+		status.Store(int32(test.status))
+		c.Timeout = 50 * time.Millisecond
+		ctx := WithStaleReport(context.Background())
+		resp, err := c.Get(ctx, "commonallplayers", nil, "", nil)
+		if err != nil {
+			t.Fatalf("%s: Get returned error: %v", test.name, err)
+		}
+		if !resp.Stale || resp.Cache != CacheStale || !ServedStale(ctx) {
+			t.Errorf("%s: Stale = %v, Cache = %q, ServedStale = %v, want a stale response", test.name, resp.Stale, resp.Cache, ServedStale(ctx))
+		}
+		if got := resp.Data.(map[string]interface{})["resource"]; got != "commonallplayers" {
+			t.Errorf("%s: resource = %v, want cached commonallplayers", test.name, got)
+		}
+	}
+}
+
+func TestCacheDoesNotServeStaleForClientErrors(t *testing.T) {
+	var missing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if missing.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"resource": "commonallplayers"}`))
+	}))
+	defer server.Close()
+
+	c := newCacheClient(server.URL+"/", CachePolicy{TTL: time.Nanosecond})
+	if _, err := c.Get(context.Background(), "commonallplayers", nil, "", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	missing.Store(true)
+	if _, err := c.Get(context.Background(), "commonallplayers", nil, "", nil); !errors.Is(err, apierror.NotFound) {
+		t.Errorf("Get error = %v, want NotFound rather than the stale entry", err)
+	}
+}
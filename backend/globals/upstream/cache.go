@@ -0,0 +1,307 @@
+package upstream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"net/http"
+	"sports_api/apierror"
+	"sports_api/background"
+	"sports_api/db"
+	"sports_api/logging"
+	"sports_api/metrics"
+	"sports_api/tracing"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StaleRetention is how long entries are kept past their TTL, for endpoints
+// without stale-while-revalidate, so they can still be served while an
+// upstream is unavailable.
+var StaleRetention = 7 * 24 * time.Hour
+
+// RevalidateTimeout bounds each background refresh started by stale-while-revalidate.
+var RevalidateTimeout = 30 * time.Second
+
+// CachePolicy controls how long an endpoint's responses are cached.
+type CachePolicy struct {
+	// TTL is how long a response is fresh; zero disables caching.
+	TTL time.Duration
+	// StaleWhileRevalidate is how long past TTL a response is still served
+	// immediately while a single background refresh replaces it. Zero means
+	// expired responses are refetched before returning.
+	StaleWhileRevalidate time.Duration
+}
+
+// HardExpiry returns how long past its TTL an entry may still be served in any case.
+func (p CachePolicy) HardExpiry() time.Duration {
+	if p.StaleWhileRevalidate > 0 {
+		return p.StaleWhileRevalidate
+	}
+	return StaleRetention
+}
+
+// cacheEntry is the metadata stored ahead of a cached body. The entry is fresh
+// until SoftExpiry and must never be served after HardExpiry.
+type cacheEntry struct {
+	Status          string
+	StatusCode      int
+	ContentType     string
+	ContentEncoding string
+	StoredAt        time.Time
+	SoftExpiry      time.Time
+	HardExpiry      time.Time
+}
+
+// encodeCacheEntry serializes entry as a line of JSON followed by the body,
+// gzipping bodies the upstream sent uncompressed.
+func encodeCacheEntry(entry cacheEntry, body []byte) ([]byte, error) {
+	if entry.ContentEncoding == "" {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		body = buf.Bytes()
+		entry.ContentEncoding = "gzip"
+	}
+
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, len(meta)+1+len(body))
+	data = append(data, meta...)
+	data = append(data, '\n')
+	return append(data, body...), nil
+}
+
+// decodeCacheEntry splits data written by encodeCacheEntry into its entry and body.
+func decodeCacheEntry(data []byte) (cacheEntry, []byte, error) {
+	var entry cacheEntry
+	meta, body, ok := bytes.Cut(data, []byte{'\n'})
+	if !ok {
+		return entry, nil, errors.New("missing cache entry header")
+	}
+	if err := json.Unmarshal(meta, &entry); err != nil {
+		return entry, nil, err
+	}
+	return entry, body, nil
+}
+
+// response rebuilds the cached Response for url and decodes its body the same way send does.
+func (e cacheEntry) response(req *Request, body []byte) (*Response, error) {
+	resp := &Response{
+		Status:     e.Status,
+		StatusCode: e.StatusCode,
+		URL:        req.URL,
+		Headers:    make(http.Header),
+		Raw:        body,
+	}
+	if e.ContentType != "" {
+		resp.Headers.Set("Content-Type", e.ContentType)
+	}
+	if e.ContentEncoding != "" {
+		resp.Headers.Set("Content-Encoding", e.ContentEncoding)
+	}
+	if err := resp.decode(req.Provider); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Cache serves responses from store keyed by the full URL, using the policy
+// policyFor selects for each endpoint. Only the raw compressed body and minimal
+// metadata are stored. Entries past their soft expiry are served immediately
+// while a background refresh runs for endpoints with StaleWhileRevalidate, and
+// otherwise only, marked Stale, when the fetch fails in a way fallsBackToStale
+// accepts. Nothing is served past its hard expiry.
+func Cache(store db.Cache, policyFor func(endpoint string) CachePolicy) Middleware {
+	var refreshing sync.Map
+	return func(next Handler) Handler {
+		fetch := func(ctx context.Context, req *Request, policy CachePolicy) (*Response, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+
+			now := time.Now()
+			entry := cacheEntry{
+				Status:          resp.Status,
+				StatusCode:      resp.StatusCode,
+				ContentType:     resp.Headers.Get("Content-Type"),
+				ContentEncoding: resp.Headers.Get("Content-Encoding"),
+				StoredAt:        now,
+				SoftExpiry:      now.Add(policy.TTL),
+				HardExpiry:      now.Add(policy.TTL + policy.HardExpiry()),
+			}
+			data, err := encodeCacheEntry(entry, resp.Raw)
+			if err != nil {
+				metrics.CacheWriteErrors.WithLabelValues(req.Provider).Inc()
+				logging.FromContext(ctx).WarnContext(ctx, "failed to encode response for cache", "url", RedactURL(req.URL), "error", err)
+			} else if err := store.Set(ctx, req.URL, data, entry.HardExpiry.Sub(now)); err != nil {
+				metrics.CacheWriteErrors.WithLabelValues(req.Provider).Inc()
+				logging.FromContext(ctx).WarnContext(ctx, "failed to cache response", "url", RedactURL(req.URL), "error", err)
+			}
+			resp.Cache = CacheMiss
+			return resp, nil
+		}
+
+		// revalidate refreshes req's entry in the background unless a refresh is
+		// already running or the server is shutting down.
+		revalidate := func(ctx context.Context, req *Request, policy CachePolicy) {
+			if _, running := refreshing.LoadOrStore(req.URL, struct{}{}); running {
+				return
+			}
+			refreshReq := &Request{URL: req.URL, Endpoint: req.Endpoint, Provider: req.Provider, Header: req.Header.Clone()}
+			started := background.Go(ctx, func(ctx context.Context) {
+				defer refreshing.Delete(req.URL)
+				ctx, cancel := context.WithTimeout(ctx, RevalidateTimeout)
+				defer cancel()
+				if _, err := fetch(ctx, refreshReq, policy); err != nil {
+					logging.FromContext(ctx).WarnContext(ctx, "background cache refresh failed", "url", RedactURL(req.URL), "error", err)
+				}
+			})
+			if !started {
+				refreshing.Delete(req.URL)
+			}
+		}
+
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			policy := policyFor(req.Endpoint)
+			if policy.TTL <= 0 {
+				return next(ctx, req)
+			}
+
+			logger := logging.FromContext(ctx).With("url", RedactURL(req.URL))
+			var stale func() (*Response, error)
+			var staleSince time.Time
+			lookupCtx, lookup := tracing.Start(ctx, "cache lookup")
+			lookupResult := "miss"
+			endLookup := func(result string) {
+				lookup.SetAttributes(attribute.String("cache.result", result))
+				lookup.End()
+			}
+			cachedData, err := store.Get(lookupCtx, req.URL)
+			if err == nil {
+				now := time.Now()
+				entry, body, err := decodeCacheEntry(cachedData)
+				switch {
+				case err != nil:
+					lookupResult = "error"
+					logger.WarnContext(ctx, "cached entry invalid, making new request", "error", err)
+				case now.Before(entry.SoftExpiry):
+					if resp, err := entry.response(req, body); err == nil {
+						logger.DebugContext(ctx, "cache hit")
+						countCache(req, "hit")
+						endLookup("hit")
+						resp.Cache = CacheHit
+						return resp, nil
+					}
+					lookupResult = "error"
+					logger.WarnContext(ctx, "cached entry invalid, making new request")
+				case now.Before(entry.HardExpiry):
+					if policy.StaleWhileRevalidate > 0 {
+						if resp, err := entry.response(req, body); err == nil {
+							logger.DebugContext(ctx, "cache stale, revalidating in background")
+							revalidate(ctx, req, policy)
+							countCache(req, "stale")
+							endLookup("stale")
+							resp.Stale = true
+							resp.Cache = CacheStale
+							return resp, nil
+						}
+						lookupResult = "error"
+						logger.WarnContext(ctx, "cached entry invalid, making new request")
+						break
+					}
+					stale = func() (*Response, error) { return entry.response(req, body) }
+					staleSince = entry.StoredAt
+					lookupResult = "expired"
+					logger.DebugContext(ctx, "cache expired")
+				default:
+					logger.DebugContext(ctx, "cache entry past hard expiry")
+				}
+			} else if errors.Is(err, db.ErrCacheMiss) {
+				logger.DebugContext(ctx, "cache miss")
+			} else {
+				lookupResult = "error"
+				logger.WarnContext(ctx, "cache read failed, making new request", "error", err)
+			}
+			endLookup(lookupResult)
+
+			// Each lookup is counted once: stale if the expired entry ends up
+			// served, otherwise error or miss as the lookup found
+			counted := lookupResult
+			if counted == "expired" {
+				counted = "miss"
+			}
+			resp, err := fetch(ctx, req, policy)
+			if err != nil {
+				if stale != nil && fallsBackToStale(err) {
+					if staleResp, staleErr := stale(); staleErr == nil {
+						logger.WarnContext(ctx, "serving stale response while upstream is unavailable", "age", time.Since(staleSince).Round(time.Second), "kind", apierror.KindOf(err).Code(), "error", err)
+						staleResp.Stale = true
+						staleResp.Cache = CacheStale
+						countCache(req, "stale")
+						return staleResp, nil
+					}
+				}
+				countCache(req, counted)
+				return nil, err
+			}
+			countCache(req, counted)
+			return resp, nil
+		}
+	}
+}
+
+// fallsBackToStale reports whether a failed fetch may be answered with an
+// expired entry: the circuit is open, the upstream timed out, failed, rate
+// limited or blocked us, or our request budget for it is spent.
+func fallsBackToStale(err error) bool {
+	switch apierror.KindOf(err) {
+	case apierror.Unavailable, apierror.Blocked, apierror.QuotaExhausted:
+		return true
+	}
+	return false
+}
+
+type staleReportKey struct{}
+
+// WithStaleReport returns a context under which Client.Do notes any response
+// it returns marked Stale, for ServedStale to report.
+func WithStaleReport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staleReportKey{}, new(atomic.Bool))
+}
+
+// ServedStale reports whether a stale response was returned under ctx, which
+// must come from WithStaleReport.
+func ServedStale(ctx context.Context) bool {
+	served, _ := ctx.Value(staleReportKey{}).(*atomic.Bool)
+	return served != nil && served.Load()
+}
+
+// reportStale notes a stale response for ServedStale.
+func reportStale(ctx context.Context) {
+	if served, ok := ctx.Value(staleReportKey{}).(*atomic.Bool); ok {
+		served.Store(true)
+	}
+}
+
+// CachePolicyFor returns the cache policy for endpoint, combining CacheTTLFor
+// with a matching EndpointStaleWhileRevalidate pattern.
+func (c *Client) CachePolicyFor(endpoint string) CachePolicy {
+	policy := CachePolicy{TTL: c.CacheTTLFor(endpoint)}
+	if window, ok := matchPattern(c.EndpointStaleWhileRevalidate, endpoint); ok {
+		policy.StaleWhileRevalidate = window
+	}
+	return policy
+}
@@ -0,0 +1,140 @@
+package upstream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/db"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newCacheClient(baseURL string, policy CachePolicy) *Client {
+	c := newTestClient(baseURL)
+	c.Use(Cache(db.NewMemoryCache(0), func(string) CachePolicy { return policy }))
+	return c
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n > 1 {
+			<-release
+		}
+		fmt.Fprintf(w, `{"version": %d}`, n)
+	}))
+	defer server.Close()
+
+	c := newCacheClient(server.URL+"/", CachePolicy{TTL: time.Millisecond, StaleWhileRevalidate: time.Hour})
+	if _, err := c.Get(context.Background(), "playergamelogs", nil, "", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// Expired entries are served without waiting, and only one refresh is started
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get(context.Background(), "playergamelogs", nil, "", nil)
+			if err != nil {
+				t.Errorf("Get returned error: %v", err)
+				return
+			}
+			if !resp.Stale || fmt.Sprint(resp.Data.(map[string]interface{})["version"]) != "1" {
+				t.Errorf("Get = %+v, want stale version 1", resp)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The refresh is blocked in the upstream; nothing else may have reached it
+	time.Sleep(20 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("upstream calls while refreshing = %d, want 2", n)
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp, err := c.Get(context.Background(), "playergamelogs", nil, "", nil)
+		if err == nil && fmt.Sprint(resp.Data.(map[string]interface{})["version"]) != "1" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("background refresh never replaced the stale entry")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCacheNeverServesPastHardExpiry(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newCacheClient(server.URL+"/", CachePolicy{TTL: time.Millisecond, StaleWhileRevalidate: time.Millisecond})
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get(context.Background(), "scoreboardv2", nil, "", nil)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+		if resp.Stale {
+			t.Errorf("Get %d served a stale response past its hard expiry", i+1)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if calls != 2 {
+		t.Errorf("upstream calls = %d, want 2", calls)
+	}
+}
+
+func TestCacheHitDecodesLikeMiss(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Unneeded", "dropped")
+		_, _ = w.Write([]byte(`{"HOME_TEAM_ID": 1610612737}`))
+	}))
+	defer server.Close()
+
+	store := db.NewMemoryCache(0)
+	c := newTestClient(server.URL + "/")
+	c.Use(Cache(store, func(string) CachePolicy { return CachePolicy{TTL: time.Hour} }))
+
+	for _, outcome := range []string{"miss", "hit"} {
+		resp, err := c.Get(context.Background(), "scoreboardv2", nil, "", nil)
+		if err != nil {
+			t.Fatalf("%s: Get returned error: %v", outcome, err)
+		}
+		id, ok := resp.Data.(map[string]interface{})["HOME_TEAM_ID"].(json.Number)
+		if !ok || id.String() != "1610612737" {
+			t.Errorf("%s: HOME_TEAM_ID = %#v, want json.Number 1610612737", outcome, resp.Data)
+		}
+	}
+
+	data, err := store.Get(context.Background(), server.URL+"/scoreboardv2")
+	if err != nil {
+		t.Fatalf("store.Get returned error: %v", err)
+	}
+	entry, body, err := decodeCacheEntry(data)
+	if err != nil {
+		t.Fatalf("decodeCacheEntry returned error: %v", err)
+	}
+	if entry.ContentEncoding != "gzip" || !bytes.HasPrefix(body, []byte{0x1f, 0x8b}) {
+		t.Errorf("cached body encoding = %q, want gzip-compressed body", entry.ContentEncoding)
+	}
+	if bytes.Contains(data, []byte("dropped")) {
+		t.Errorf("cached entry stores response headers beyond the content metadata")
+	}
+}
@@ -0,0 +1,293 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"net/url"
+	"sports_api/apierror"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/tracing"
+	"strings"
+	"time"
+)
+
+// Request describes a single GET against an upstream provider.
+type Request struct {
+	URL      string      // Fully prepared URL including the query string
+	Endpoint string      // Endpoint relative to the client's BaseURL
+	Provider string      // Name of the client issuing the request, e.g. "NBA"
+	Header   http.Header // Headers set by the caller; defaults are merged in by the Headers middleware
+	// Secrets are query parameters, such as API keys, added only when the
+	// request is sent so they never reach cache keys, logs or traces.
+	Secrets url.Values
+}
+
+// Handler executes a Request and returns the decoded Response.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler with additional behaviour such as caching or retries.
+type Middleware func(next Handler) Handler
+
+// Client is the shared HTTP client every league and odds session builds on.
+type Client struct {
+	Name           string // Provider name used in logs and errors, e.g. "NBA"
+	HTTPClient     *http.Client
+	BaseURL        string
+	DefaultHeaders map[string]string
+	Proxy          string
+	Timeout        time.Duration // Deadline applied to each call, including cache lookups
+	Middleware     []Middleware  // Applied in order, the first entry being the outermost
+
+	// RetryPolicy applies to every endpoint unless one of EndpointRetryPolicies matches.
+	RetryPolicy RetryPolicy
+	// EndpointRetryPolicies overrides RetryPolicy for endpoints matching a
+	// path.Match pattern; the most specific of several matching patterns wins.
+	EndpointRetryPolicies map[string]RetryPolicy
+
+	// Limiter throttles every request that reaches the network, including retries.
+	Limiter *Limiter
+	// Health records the outcome of every attempt that reaches the network; nil disables it.
+	Health *HealthTracker
+	// Cache is the store used by the default Cache middleware.
+	Cache db.Cache
+	// CacheTTL applies to every endpoint unless one of EndpointCacheTTLs matches.
+	CacheTTL time.Duration
+	// EndpointCacheTTLs overrides CacheTTL for endpoints matching a path.Match
+	// pattern; the most specific of several matching patterns wins.
+	EndpointCacheTTLs map[string]time.Duration
+	// CacheTTLOverrides takes precedence over EndpointCacheTTLs, matched the
+	// same way. Providers set it from config.Cache.TTLOverrides.
+	CacheTTLOverrides map[string]time.Duration
+	// EndpointStaleWhileRevalidate opts endpoints matching a path.Match pattern into
+	// serving expired responses for the given window while refreshing in the background.
+	EndpointStaleWhileRevalidate map[string]time.Duration
+}
+
+// NewClient initializes a Client with the default middleware stack:
+// header policy, tracing, logging, request coalescing, caching in cache with per-endpoint
+// TTLs, the shared circuit breaker and retries. A nil cache disables caching.
+func NewClient(name, baseURL string, cache db.Cache, defaultHeaders map[string]string) *Client {
+	c := &Client{
+		Name: name,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		BaseURL:        baseURL,
+		DefaultHeaders: defaultHeaders,
+		Timeout:        20 * time.Second,
+		RetryPolicy:    DefaultRetryPolicy,
+		Limiter:        DefaultLimiter,
+		Health:         DefaultHealth,
+		Cache:          cache,
+		CacheTTL:       DefaultCacheTTL,
+	}
+	c.Middleware = append(c.LocalMiddleware(), c.NetworkMiddleware()...)
+	return c
+}
+
+// LocalMiddleware returns the outer half of the default stack, which can
+// answer a request without reaching the upstream: header policy, tracing,
+// logging, request coalescing and, when the client has a Cache, caching.
+// Clients that need middleware between the halves build their chain from
+// LocalMiddleware and NetworkMiddleware.
+func (c *Client) LocalMiddleware() []Middleware {
+	mw := []Middleware{
+		Headers(c.DefaultHeaders),
+		Tracing(c.Name),
+		Logging(c.Name),
+		Coalesce(func() time.Duration { return c.Timeout }),
+	}
+	if c.Cache != nil {
+		mw = append(mw, Cache(c.Cache, c.CachePolicyFor))
+	}
+	return mw
+}
+
+// NetworkMiddleware returns the inner half of the default stack, which only
+// sees requests bound for the upstream: the shared circuit breaker and retries.
+func (c *Client) NetworkMiddleware() []Middleware {
+	return []Middleware{CircuitBreaker(DefaultBreaker), Retry(c.RetryPolicyFor)}
+}
+
+// Configure applies a provider's settings from cfg to the client.
+func (c *Client) Configure(cfg config.Upstream) error {
+	if cfg.BaseURL != "" {
+		c.BaseURL = cfg.BaseURL
+	}
+	if cfg.Timeout > 0 {
+		c.Timeout = cfg.Timeout
+	}
+	if cfg.Proxy != "" {
+		return c.SetProxy(cfg.Proxy)
+	}
+	return nil
+}
+
+// SetProxy routes the client's requests through proxyURL.
+func (c *Client) SetProxy(proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("error parsing proxy URL: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(u)
+	c.HTTPClient.Transport = transport
+	c.Proxy = proxyURL
+	return nil
+}
+
+// Use appends middleware to the end of the client's chain.
+func (c *Client) Use(mw ...Middleware) {
+	c.Middleware = append(c.Middleware, mw...)
+}
+
+// Get requests endpoint relative to the client's BaseURL.
+func (c *Client) Get(ctx context.Context, endpoint string, params map[string]string, referer string, customHeaders map[string]string) (*Response, error) {
+	return c.GetURL(ctx, c.BaseURL+endpoint, params, referer, customHeaders)
+}
+
+// GetURL requests an absolute URL, merging params into its query string.
+func (c *Client) GetURL(ctx context.Context, rawURL string, params map[string]string, referer string, customHeaders map[string]string) (*Response, error) {
+	fullURL, err := PrepareURL(rawURL, params)
+	if err != nil {
+		return nil, fmt.Errorf("error constructing request URL: %w", err)
+	}
+
+	req := &Request{
+		URL:      fullURL,
+		Endpoint: strings.TrimPrefix(rawURL, c.BaseURL),
+		Provider: c.Name,
+		Header:   make(http.Header),
+	}
+	for k, v := range customHeaders {
+		req.Header.Set(k, v)
+	}
+	if referer != "" {
+		req.Header.Set("Referer", referer)
+	}
+
+	return c.Do(ctx, req)
+}
+
+// Do runs req through the middleware chain and the HTTP transport.
+// Each call is bounded by the client's Timeout so slow upstreams release their
+// goroutines; a shorter deadline already set on ctx takes precedence. A stale
+// response is noted on ctx for ServedStale.
+func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	handler := c.send
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		handler = c.Middleware[i](handler)
+	}
+	resp, err := handler(ctx, req)
+	if err == nil && resp.Stale {
+		reportStale(ctx)
+	}
+	return resp, err
+}
+
+// send executes the request against the upstream and decodes the body.
+// The host's rate limit and concurrency slot are held until the body is read.
+func (c *Client) send(ctx context.Context, req *Request) (_ *Response, err error) {
+	ctx, span := tracing.Start(ctx, "HTTP GET", trace.WithSpanKind(trace.SpanKindClient))
+	defer tracing.End(span, &err)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	httpReq.Header = req.Header.Clone()
+	if len(req.Secrets) > 0 {
+		query := httpReq.URL.Query()
+		for key, values := range req.Secrets {
+			query[key] = values
+		}
+		httpReq.URL.RawQuery = query.Encode()
+	}
+
+	if c.Limiter != nil {
+		release, err := c.Limiter.Acquire(ctx, httpReq.URL.Hostname())
+		if err != nil {
+			return nil, fmt.Errorf("waiting for %s rate limit: %w", httpReq.URL.Hostname(), err)
+		}
+		defer release()
+		span.AddEvent("rate limit acquired")
+	}
+	if c.Health != nil {
+		defer func() { c.Health.Record(req.Provider, err) }()
+	}
+
+	httpClient := c.HTTPClient
+	if recorder := activeRecorder.Load(); recorder != nil {
+		recording := *httpClient
+		recording.Transport = recorder.Wrap(httpClient.Transport)
+		httpClient = &recording
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		observeAttempt(req, start, 0)
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = RedactURL(req.URL) // Without Secrets
+		}
+		return nil, apierror.New(apierror.Unavailable, "error making request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		observeAttempt(req, start, resp.StatusCode)
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		observeAttempt(req, start, 0)
+		return nil, apierror.New(apierror.Unavailable, "failed to read response body: %w", err)
+	}
+
+	response := &Response{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		URL:        req.URL,
+		Headers:    resp.Header,
+		Raw:        raw,
+	}
+	observeAttempt(req, start, resp.StatusCode)
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode), attribute.Int("http.response.body.size", len(raw)))
+	if err := response.decode(c.Name); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// PrepareURL constructs a full URL with query parameters.
+func PrepareURL(rawURL string, params map[string]string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing base URL: %w", err)
+	}
+
+	q := u.Query()
+	for key, value := range params {
+		q.Set(key, value)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
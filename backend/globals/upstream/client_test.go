@@ -0,0 +1,275 @@
+package upstream
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/apierror"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(baseURL string) *Client {
+	c := NewClient("Test", baseURL, nil, map[string]string{
+		"Accept":     "application/json",
+		"User-Agent": "default-agent",
+	})
+	c.Middleware = []Middleware{Headers(c.DefaultHeaders)}
+	return c
+}
+
+func TestClientGetMergesHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != "application/json" {
+			t.Errorf("Accept = %q, want default header", got)
+		}
+		if got := r.Header.Get("User-Agent"); got != "custom-agent" {
+			t.Errorf("User-Agent = %q, want custom header to override default", got)
+		}
+		if got := r.Header.Get("Referer"); got != "https://example.com/" {
+			t.Errorf("Referer = %q, want referer argument", got)
+		}
+		if got := r.URL.Query().Get("Season"); got != "2024-25" {
+			t.Errorf("Season = %q, want query parameter", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1610612737}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL + "/")
+	resp, err := c.Get(context.Background(), "teams", map[string]string{"Season": "2024-25"}, "https://example.com/", map[string]string{"User-Agent": "custom-agent"})
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	data := resp.Data.(map[string]interface{})
+	if data["id"].(interface{ String() string }).String() != "1610612737" {
+		t.Errorf("id = %v, want 1610612737 decoded as json.Number", data["id"])
+	}
+}
+
+func TestClientGetDecodesGzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`{"resource": "scoreboard"}`))
+		_ = gz.Close()
+	}))
+	defer server.Close()
+
+	resp, err := newTestClient(server.URL+"/").Get(context.Background(), "scoreboard", nil, "", nil)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if got := resp.Data.(map[string]interface{})["resource"]; got != "scoreboard" {
+		t.Errorf("resource = %v, want scoreboard", got)
+	}
+}
+
+func TestClientGetErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		kind        apierror.Kind
+	}{
+		{"non-200 status", http.StatusInternalServerError, "application/json", `{}`, apierror.Unavailable},
+		{"not found", http.StatusNotFound, "application/json", `{}`, apierror.NotFound},
+		{"rejected parameters", http.StatusBadRequest, "application/json", `{}`, apierror.Validation},
+		{"forbidden", http.StatusForbidden, "text/html", "Access Denied", apierror.Blocked},
+		{"HTML error page", http.StatusOK, "text/html; charset=utf-8", "<html>Access Denied</html>", apierror.Blocked},
+		{"invalid JSON", http.StatusOK, "application/json", "{", apierror.Decode},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", test.contentType)
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			_, err := newTestClient(server.URL+"/").Get(context.Background(), "endpoint", nil, "", nil)
+			if err == nil {
+				t.Fatalf("Expected error but got nil")
+			}
+			if !errors.Is(err, test.kind) || apierror.KindOf(err) != test.kind {
+				t.Errorf("error %v has kind %s, want %s", err, apierror.KindOf(err).Code(), test.kind.Code())
+			}
+		})
+	}
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	if _, err := newTestClient(server.URL+"/").Get(context.Background(), "endpoint", nil, "", nil); apierror.KindOf(err) != apierror.Unavailable {
+		t.Errorf("connection error %v has kind %s, want upstream_unavailable", err, apierror.KindOf(err).Code())
+	}
+}
+
+func TestClientMiddlewareOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				order = append(order, name)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	c := newTestClient(server.URL + "/")
+	c.Use(record("first"), record("second"))
+	if _, err := c.Get(context.Background(), "endpoint", nil, "", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("middleware order = %v, want [first second]", order)
+	}
+}
+
+func TestClientGetHonoursCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := newTestClient(server.URL + "/")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := c.Get(ctx, "slow", nil, "", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get error = %v, want context.Canceled", err)
+	}
+
+	c.Timeout = 50 * time.Millisecond
+	if _, err := c.Get(context.Background(), "slow", nil, "", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestCoalesceSharesInFlightRequest(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL + "/")
+	c.Use(Coalesce(func() time.Duration { return c.Timeout }))
+
+	// The first caller gives up early; the others must still get the shared response
+	cancelled, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.Background()
+			if i == 0 {
+				ctx = cancelled
+			}
+			_, errs[i] = c.Get(ctx, "playergamelogs", map[string]string{"Season": "2024-25"}, "", nil)
+		}(i)
+		if i == 0 {
+			// Let the cancelled caller start the shared call
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("upstream calls = %d, want 1", calls)
+	}
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("cancelled caller error = %v, want context.DeadlineExceeded", errs[0])
+	}
+	for i, err := range errs[1:] {
+		if err != nil {
+			t.Errorf("caller %d returned error: %v", i+1, err)
+		}
+	}
+}
+
+func TestCoalesceGivesCallersIndependentCopies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("X-Test", "upstream")
+		_, _ = w.Write([]byte(`{"players": ["a"]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL + "/")
+	c.Use(Coalesce(func() time.Duration { return c.Timeout }))
+
+	var wg sync.WaitGroup
+	resps := make([]*Response, 2)
+	for i := range resps {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := c.Get(context.Background(), "players", nil, "", nil)
+			if err != nil {
+				t.Errorf("caller %d returned error: %v", i, err)
+			}
+			resps[i] = resp
+		}(i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	resps[0].Data.(map[string]interface{})["players"] = nil
+	resps[0].Headers.Set("X-Test", "changed")
+	resps[0].Raw[0] = 'x'
+	if other := resps[1]; other.Data.(map[string]interface{})["players"] == nil ||
+		other.Headers.Get("X-Test") != "upstream" || other.Raw[0] != '{' {
+		t.Errorf("changing one caller's response changed the other's: %+v", other)
+	}
+}
+
+func TestCoalesceBoundsSharedCallByTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL + "/")
+	c.Timeout = 0 // Leave the deadline to Coalesce alone
+	c.Use(Coalesce(func() time.Duration { return 50 * time.Millisecond }))
+
+	if _, err := c.Get(context.Background(), "slow", nil, "", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get error = %v, want context.DeadlineExceeded from the shared call", err)
+	}
+}
@@ -0,0 +1,15 @@
+package upstream
+
+import "sports_api/config"
+
+// Configure applies the process-wide settings in cfg: the per-host rate
+// limits shared by every client.
+func Configure(cfg *config.Config) {
+	for host, limit := range cfg.RateLimits {
+		DefaultLimiter.SetLimit(host, HostLimit{
+			Rate:        limit.Rate,
+			Burst:       limit.Burst,
+			MaxInFlight: limit.MaxInFlight,
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package upstream
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Decoder wraps a body compressed with one content coding in a reader of the decoded content.
+type Decoder func(r io.Reader) (io.ReadCloser, error)
+
+// contentDecoders are the content codings every client can decode, in order of preference.
+var contentDecoders = []struct {
+	coding string
+	decode Decoder
+}{
+	{"gzip", func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }},
+	{"deflate", func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) }},
+	{"br", func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(brotli.NewReader(r)), nil }},
+	{"zstd", func(r io.Reader) (io.ReadCloser, error) {
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}},
+}
+
+// decoderFor returns the Decoder for coding, or nil if it is not supported.
+func decoderFor(coding string) Decoder {
+	for _, d := range contentDecoders {
+		if strings.EqualFold(d.coding, coding) {
+			return d.decode
+		}
+	}
+	return nil
+}
+
+// AcceptEncoding is the Accept-Encoding header value listing every supported content coding.
+func AcceptEncoding() string {
+	codings := make([]string, len(contentDecoders))
+	for i, d := range contentDecoders {
+		codings[i] = d.coding
+	}
+	return strings.Join(codings, ", ")
+}
+
+// supportedEncodings filters an Accept-Encoding value down to the codings
+// that can be decoded, keeping identity and any quality values.
+func supportedEncodings(value string) string {
+	var kept []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		coding, _, _ := strings.Cut(item, ";")
+		coding = strings.TrimSpace(coding)
+		if strings.EqualFold(coding, "identity") || decoderFor(coding) != nil {
+			kept = append(kept, item)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
+
+// decodeContent wraps body in readers undoing each coding in a Content-Encoding
+// value, which lists codings in the order they were applied.
+func decodeContent(body io.Reader, contentEncoding string) (io.Reader, func(), error) {
+	var closers []io.Closer
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i].Close()
+		}
+	}
+
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.TrimSpace(codings[i])
+		if coding == "" || strings.EqualFold(coding, "identity") {
+			continue
+		}
+		decode := decoderFor(coding)
+		if decode == nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("unsupported content encoding %q", coding)
+		}
+		reader, err := decode(body)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("failed to create %s reader: %w", coding, err)
+		}
+		closers = append(closers, reader)
+		body = reader
+	}
+	return body, closeAll, nil
+}
@@ -0,0 +1,90 @@
+package upstream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func compress(t *testing.T, coding string, body []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch coding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "zstd":
+		var err error
+		if w, err = zstd.NewWriter(&buf); err != nil {
+			t.Fatalf("zstd.NewWriter returned error: %v", err)
+		}
+	default:
+		return body
+	}
+	w.Write(body)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDecodesEveryAdvertisedEncoding(t *testing.T) {
+	var accepted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted = r.Header.Get("Accept-Encoding")
+		coding := r.URL.Query().Get("coding")
+		body := []byte(`{"resultSets": []}`)
+		for _, c := range strings.Split(coding, ", ") {
+			body = compress(t, c, body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", coding)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	c := NewClient("Test", server.URL+"/", nil, map[string]string{"Accept-Encoding": AcceptEncoding()})
+	for _, coding := range []string{"gzip", "deflate", "br", "zstd", "identity", "gzip, br"} {
+		resp, err := c.Get(context.Background(), "x", map[string]string{"coding": coding}, "", nil)
+		if err != nil {
+			t.Errorf("%s response returned error: %v", coding, err)
+			continue
+		}
+		if _, ok := resp.Data.(map[string]interface{})["resultSets"]; !ok {
+			t.Errorf("%s response data = %v, want resultSets", coding, resp.Data)
+		}
+	}
+	if accepted != "gzip, deflate, br, zstd" {
+		t.Errorf("Accept-Encoding = %q, want every supported coding", accepted)
+	}
+
+	if _, err := c.Get(context.Background(), "x", map[string]string{"coding": "compress"}, "", nil); err == nil || !strings.Contains(err.Error(), "unsupported content encoding") {
+		t.Errorf("compress response error = %v, want unsupported content encoding", err)
+	}
+}
+
+func TestSupportedEncodings(t *testing.T) {
+	tests := map[string]string{
+		"gzip, deflate, br, zstd":        "gzip, deflate, br, zstd",
+		"gzip, compress, br;q=0.5, sdch": "gzip, br;q=0.5",
+		"identity, exi":                  "identity",
+		"compress":                       "",
+		"GZIP":                           "GZIP",
+	}
+	for value, want := range tests {
+		if got := supportedEncodings(value); got != want {
+			t.Errorf("supportedEncodings(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
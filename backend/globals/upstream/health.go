@@ -0,0 +1,71 @@
+package upstream
+
+import (
+	"sync"
+	"time"
+)
+
+// ProviderHealth is a snapshot of the outcome of a provider's upstream calls.
+type ProviderHealth struct {
+	Provider    string    `json:"provider"`
+	LastSuccess time.Time `json:"lastSuccess,omitzero"`
+	LastError   time.Time `json:"lastError,omitzero"`
+	Error       string    `json:"error,omitempty"` // The most recent failure
+}
+
+// Failing reports whether the provider's most recent call failed.
+func (p ProviderHealth) Failing() bool {
+	return p.LastError.After(p.LastSuccess)
+}
+
+// HealthTracker records when each provider last answered and last failed.
+// Outcomes are judged like the circuit breaker judges them: client errors
+// such as 404 show the upstream is up, and cancelled calls are ignored.
+type HealthTracker struct {
+	mu        sync.Mutex
+	providers map[string]*ProviderHealth
+	now       func() time.Time
+}
+
+// DefaultHealth is shared by every client so readiness sees all providers.
+var DefaultHealth = NewHealthTracker()
+
+// NewHealthTracker creates an empty HealthTracker.
+func NewHealthTracker() *HealthTracker {
+	return &HealthTracker{
+		providers: make(map[string]*ProviderHealth),
+		now:       time.Now,
+	}
+}
+
+// Record notes the outcome of a call to provider.
+func (h *HealthTracker) Record(provider string, err error) {
+	result := outcome(err)
+	if result == outcomeNeutral {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p, ok := h.providers[provider]
+	if !ok {
+		p = &ProviderHealth{Provider: provider}
+		h.providers[provider] = p
+	}
+	if result == outcomeSuccess {
+		p.LastSuccess = h.now()
+		return
+	}
+	p.LastError = h.now()
+	p.Error = err.Error()
+}
+
+// Get returns provider's health; a provider that has not been called yet has zero times.
+func (h *HealthTracker) Get(provider string) ProviderHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if p, ok := h.providers[provider]; ok {
+		return *p
+	}
+	return ProviderHealth{Provider: provider}
+}
@@ -0,0 +1,42 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHealthTrackerJudgesOutcomesLikeTheBreaker(t *testing.T) {
+	h := NewHealthTracker()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	if got := h.Get("NBA"); got.Provider != "NBA" || !got.LastSuccess.IsZero() || got.Failing() {
+		t.Errorf("uncalled provider = %+v, want zero times", got)
+	}
+
+	h.Record("NBA", nil)
+	now = now.Add(time.Minute)
+	h.Record("NBA", &StatusError{StatusCode: http.StatusNotFound})
+	if got := h.Get("NBA"); !got.LastSuccess.Equal(now) || got.Failing() {
+		t.Errorf("after a 404 = %+v, want it counted as a success", got)
+	}
+
+	now = now.Add(time.Minute)
+	h.Record("NBA", &StatusError{StatusCode: http.StatusForbidden})
+	h.Record("NBA", context.Canceled)
+	got := h.Get("NBA")
+	if !got.Failing() || !got.LastError.Equal(now) || got.Error != "unexpected response status: 403" {
+		t.Errorf("after a 403 = %+v, want it failing with the 403", got)
+	}
+
+	now = now.Add(time.Minute)
+	h.Record("NBA", nil)
+	if got := h.Get("NBA"); got.Failing() || got.Error == "" {
+		t.Errorf("after recovering = %+v, want not failing but the last error kept", got)
+	}
+	if h.Get("Odds").Failing() {
+		t.Error("outcomes leaked between providers")
+	}
+}
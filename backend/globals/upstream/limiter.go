@@ -0,0 +1,94 @@
+package upstream
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// HostLimit bounds the request rate and concurrency for a single upstream host.
+type HostLimit struct {
+	Rate        float64 // Sustained requests per second; 0 means unlimited
+	Burst       int     // Requests allowed in a burst above Rate
+	MaxInFlight int     // Concurrent requests allowed; 0 means unlimited
+}
+
+// DefaultHostLimits keeps each provider well below the rates that get our IP blocked.
+var DefaultHostLimits = map[string]HostLimit{
+	"stats.nba.com":        {Rate: 2, Burst: 4, MaxInFlight: 4},
+	"api.nhle.com":         {Rate: 5, Burst: 10, MaxInFlight: 8},
+	"api-web.nhle.com":     {Rate: 10, Burst: 20, MaxInFlight: 8},
+	"statsapi.mlb.com":     {Rate: 10, Burst: 20, MaxInFlight: 8},
+	"api.the-odds-api.com": {Rate: 5, Burst: 5, MaxInFlight: 4},
+}
+
+// DefaultLimiter is shared by every client so limits hold across sessions hitting the same host.
+var DefaultLimiter = NewLimiter(DefaultHostLimits)
+
+// Limiter enforces a token bucket and a max-in-flight semaphore per host.
+type Limiter struct {
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+type hostLimiter struct {
+	tokens *rate.Limiter
+	slots  chan struct{}
+}
+
+// NewLimiter creates a Limiter with the given per-host limits. Hosts without a
+// limit are not throttled.
+func NewLimiter(limits map[string]HostLimit) *Limiter {
+	l := &Limiter{hosts: make(map[string]*hostLimiter)}
+	for host, limit := range limits {
+		l.SetLimit(host, limit)
+	}
+	return l
+}
+
+// SetLimit replaces the limit for host. Requests already holding a slot are unaffected.
+func (l *Limiter) SetLimit(host string, limit HostLimit) {
+	h := &hostLimiter{tokens: rate.NewLimiter(rate.Inf, 0)}
+	if limit.Rate > 0 {
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		h.tokens = rate.NewLimiter(rate.Limit(limit.Rate), burst)
+	}
+	if limit.MaxInFlight > 0 {
+		h.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+
+	l.mu.Lock()
+	l.hosts[host] = h
+	l.mu.Unlock()
+}
+
+// Acquire blocks until host has a free slot and a token, or ctx is done.
+// The returned release must be called once the request has finished.
+func (l *Limiter) Acquire(ctx context.Context, host string) (release func(), err error) {
+	l.mu.Lock()
+	h := l.hosts[host]
+	l.mu.Unlock()
+	if h == nil {
+		return func() {}, nil
+	}
+
+	release = func() {}
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+			release = func() { <-h.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := h.tokens.Wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
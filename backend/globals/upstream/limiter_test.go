@@ -0,0 +1,86 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiterCapsInFlightRequests(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	c := newTestClient(server.URL + "/")
+	c.Limiter = NewLimiter(map[string]HostLimit{u.Hostname(): {MaxInFlight: 2}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get(context.Background(), "endpoint", nil, "", nil); err != nil {
+				t.Errorf("Get returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("peak in-flight requests = %d, want at most 2", peak)
+	}
+}
+
+func TestLimiterRate(t *testing.T) {
+	l := NewLimiter(map[string]HostLimit{"example.com": {Rate: 20, Burst: 1}})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		release, err := l.Acquire(context.Background(), "example.com")
+		if err != nil {
+			t.Fatalf("Acquire returned error: %v", err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("5 requests at 20/s took %s, want at least 150ms", elapsed)
+	}
+
+	// Hosts without a limit are never throttled
+	if _, err := l.Acquire(context.Background(), "other.com"); err != nil {
+		t.Errorf("Acquire for unlimited host returned error: %v", err)
+	}
+}
+
+func TestLimiterAcquireHonoursContext(t *testing.T) {
+	l := NewLimiter(map[string]HostLimit{"example.com": {MaxInFlight: 1}})
+	release, err := l.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Acquire returned error: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,86 @@
+package upstream
+
+import (
+	"net/url"
+	"regexp"
+	"sports_api/metrics"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	metrics.Registry.MustRegister(breakerCollector{DefaultBreaker})
+}
+
+// idSegment matches path segments that identify a single resource, such as
+// game, player and event IDs, which would give every request its own series.
+var idSegment = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{16,}|[0-9a-fA-F-]{36})$`)
+
+// endpointLabel reduces endpoint to a bounded metric label: the path only,
+// with ID segments replaced by ":id".
+func endpointLabel(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil {
+		endpoint = u.Path
+	}
+	segments := strings.Split(endpoint, "/")
+	for i, segment := range segments {
+		if idSegment.MatchString(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// observeAttempt records one attempt at req that started at start and ended
+// with status, or 0 if no response arrived.
+func observeAttempt(req *Request, start time.Time, status int) {
+	metrics.UpstreamDuration.WithLabelValues(req.Provider, endpointLabel(req.Endpoint)).Observe(time.Since(start).Seconds())
+	code := "error"
+	if status > 0 {
+		code = strconv.Itoa(status)
+	}
+	metrics.UpstreamResponses.WithLabelValues(req.Provider, code).Inc()
+}
+
+// countCache records a cache lookup for req with result hit, stale, miss or error.
+func countCache(req *Request, result string) {
+	metrics.CacheRequests.WithLabelValues(req.Provider, result).Inc()
+}
+
+var (
+	circuitStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "upstream", "circuit_state"),
+		"Circuit breaker state per host: 0 closed, 1 open, 2 half-open.",
+		[]string{"host"}, nil,
+	)
+	circuitTripsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "upstream", "circuit_trips_total"),
+		"Times each host's circuit has opened.",
+		[]string{"host"}, nil,
+	)
+	circuitRecoveriesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "upstream", "circuit_recoveries_total"),
+		"Times a probe has closed each host's circuit again.",
+		[]string{"host"}, nil,
+	)
+)
+
+// breakerCollector reports a Breaker's per-host state at scrape time.
+type breakerCollector struct{ b *Breaker }
+
+func (c breakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- circuitStateDesc
+	ch <- circuitTripsDesc
+	ch <- circuitRecoveriesDesc
+}
+
+func (c breakerCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.b.Stats() {
+		ch <- prometheus.MustNewConstMetric(circuitStateDesc, prometheus.GaugeValue, float64(s.State), s.Host)
+		ch <- prometheus.MustNewConstMetric(circuitTripsDesc, prometheus.CounterValue, float64(s.Trips), s.Host)
+		ch <- prometheus.MustNewConstMetric(circuitRecoveriesDesc, prometheus.CounterValue, float64(s.Recoveries), s.Host)
+	}
+}
@@ -0,0 +1,131 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/db"
+	"sports_api/metrics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestEndpointLabel(t *testing.T) {
+	tests := []struct{ endpoint, want string }{
+		{"stats/playergamelogs", "stats/playergamelogs"},
+		{"v1/gamecenter/2024020001/boxscore", "v1/gamecenter/:id/boxscore"},
+		{"v4/sports/basketball_nba/events/0a1b2c3d4e5f60718293a4b5c6d7e8f9/odds", "v4/sports/basketball_nba/events/:id/odds"},
+		{"v1/roster/TOR/20242025", "v1/roster/TOR/:id"},
+		{"https://statsapi.mlb.com/api/v1/people/660271?hydrate=stats", "/api/v1/people/:id"},
+	}
+	for _, test := range tests {
+		if got := endpointLabel(test.endpoint); got != test.want {
+			t.Errorf("endpointLabel(%q) = %q, want %q", test.endpoint, got, test.want)
+		}
+	}
+}
+
+func TestMetricsRecordAttemptsAndCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("MetricsTest", server.URL+"/", nil, nil)
+	c.Middleware = []Middleware{Cache(db.NewMemoryCache(0), func(string) CachePolicy { return CachePolicy{TTL: time.Hour} })}
+	for range 2 {
+		if _, err := c.Get(context.Background(), "games/123", nil, "", nil); err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+	}
+	if _, err := c.Get(context.Background(), "missing", nil, "", nil); err == nil {
+		t.Fatal("Get of a missing endpoint succeeded")
+	}
+
+	for _, status := range []string{"200", "404"} {
+		if got := testutil.ToFloat64(metrics.UpstreamResponses.WithLabelValues("MetricsTest", status)); got != 1 {
+			t.Errorf("%s responses = %v, want 1", status, got)
+		}
+	}
+	if got := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("MetricsTest", "hit")); got != 1 {
+		t.Errorf("cache hits = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("MetricsTest", "miss")); got != 2 {
+		t.Errorf("cache misses = %v, want 2", got)
+	}
+	var latency dto.Metric
+	metrics.UpstreamDuration.WithLabelValues("MetricsTest", "games/:id").(prometheus.Metric).Write(&latency)
+	if got := latency.GetHistogram().GetSampleCount(); got != 1 {
+		t.Errorf("games/:id latency observed %d times, want once", got)
+	}
+}
+
+func TestBreakerCollectorReportsTripsAndRecoveries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewBreaker(BreakerSettings{FailureThreshold: 1, Cooldown: time.Minute})
+	b.now = func() time.Time { return now }
+
+	// Trip, let the probe through after the cooldown and recover
+	_ = b.Allow("stats.nba.com")
+	b.Record("stats.nba.com", &StatusError{StatusCode: http.StatusServiceUnavailable})
+	now = now.Add(time.Minute)
+	_ = b.Allow("stats.nba.com")
+	b.Record("stats.nba.com", nil)
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(breakerCollector{b})
+	want := `
+# HELP sports_api_upstream_circuit_recoveries_total Times a probe has closed each host's circuit again.
+# TYPE sports_api_upstream_circuit_recoveries_total counter
+sports_api_upstream_circuit_recoveries_total{host="stats.nba.com"} 1
+# HELP sports_api_upstream_circuit_trips_total Times each host's circuit has opened.
+# TYPE sports_api_upstream_circuit_trips_total counter
+sports_api_upstream_circuit_trips_total{host="stats.nba.com"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "sports_api_upstream_circuit_trips_total", "sports_api_upstream_circuit_recoveries_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+// brokenCache fails every read and write.
+type brokenCache struct{ db.Cache }
+
+func (brokenCache) Get(context.Context, string) ([]byte, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (brokenCache) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func TestMetricsCountEachCacheLookupOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("CacheLookupTest", server.URL+"/", nil, nil)
+	c.Middleware = []Middleware{Cache(brokenCache{}, func(string) CachePolicy { return CachePolicy{TTL: time.Hour} })}
+	if _, err := c.Get(context.Background(), "games", nil, "", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	for result, want := range map[string]float64{"error": 1, "miss": 0, "hit": 0} {
+		if got := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("CacheLookupTest", result)); got != want {
+			t.Errorf("cache %s = %v, want %v for one failed lookup", result, got, want)
+		}
+	}
+	if got := testutil.ToFloat64(metrics.CacheWriteErrors.WithLabelValues("CacheLookupTest")); got != 1 {
+		t.Errorf("cache write errors = %v, want 1", got)
+	}
+}
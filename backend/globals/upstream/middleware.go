@@ -0,0 +1,149 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sports_api/logging"
+	"sports_api/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// Headers merges defaults into every request without overriding headers set by
+// the caller, then drops any content coding from Accept-Encoding that the
+// client cannot decode.
+func Headers(defaults map[string]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			for k, v := range defaults {
+				if req.Header.Get(k) == "" {
+					req.Header.Set(k, v)
+				}
+			}
+			if accept := req.Header.Get("Accept-Encoding"); accept != "" {
+				if supported := supportedEncodings(accept); supported != "" {
+					req.Header.Set("Accept-Encoding", supported)
+				} else {
+					req.Header.Del("Accept-Encoding")
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// Logging logs every request with the logger carried by ctx, so each line
+// is tagged with the inbound request that caused it. Secret query parameters
+// are redacted from the logged URL.
+func Logging(provider string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+
+			attrs := []any{
+				"provider", provider,
+				"host", hostOf(req.URL),
+				"endpoint", req.Endpoint,
+				"duration", time.Since(start),
+				"url", RedactURL(req.URL),
+			}
+			logger := logging.FromContext(ctx)
+			if err != nil {
+				var statusErr *StatusError
+				if errors.As(err, &statusErr) {
+					attrs = append(attrs, "status", statusErr.StatusCode)
+				}
+				logger.WarnContext(ctx, "upstream request failed", append(attrs, "error", err)...)
+				return nil, err
+			}
+
+			cache := string(resp.Cache)
+			if cache == "" {
+				cache = "none"
+			}
+			logger.InfoContext(ctx, "upstream request", append(attrs, "status", resp.StatusCode, "cache", cache)...)
+			return resp, nil
+		}
+	}
+}
+
+// Tracing records a client span around each request, covering the cache
+// lookup, retries and the attempts made, each of which gets a span of its own.
+func Tracing(provider string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (resp *Response, err error) {
+			endpoint := endpointLabel(req.Endpoint)
+			ctx, span := tracing.Start(ctx, provider+" "+endpoint,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("upstream.provider", provider),
+					attribute.String("upstream.endpoint", endpoint),
+					semconv.ServerAddress(hostOf(req.URL)),
+					semconv.URLFull(RedactURL(req.URL)),
+				),
+			)
+			defer tracing.End(span, &err)
+
+			resp, err = next(ctx, req)
+			var statusErr *StatusError
+			switch {
+			case err == nil:
+				span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode), attribute.String("upstream.cache", string(resp.Cache)))
+			case errors.As(err, &statusErr):
+				span.SetAttributes(semconv.HTTPResponseStatusCode(statusErr.StatusCode))
+			}
+			return resp, err
+		}
+	}
+}
+
+// hostOf returns rawURL's host, or "" if it does not parse.
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// Coalesce shares one upstream round trip among concurrent callers requesting the same URL.
+// Place it outside Cache so only the shared call reads and writes the cache.
+// The shared call is detached from the first caller's cancellation and deadline
+// and bounded by timeout instead, read on each call; each caller still stops
+// waiting when its own context is done. Callers sharing a response each get
+// their own copy, decoded again from the raw body.
+func Coalesce(timeout func() time.Duration) Middleware {
+	var group singleflight.Group
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			ch := group.DoChan(req.URL, func() (interface{}, error) {
+				shared, cancel := context.WithoutCancel(ctx), context.CancelFunc(func() {})
+				if d := timeout(); d > 0 {
+					shared, cancel = context.WithTimeout(shared, d)
+				}
+				defer cancel()
+				return next(shared, req)
+			})
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case result := <-ch:
+				if result.Err != nil {
+					return nil, result.Err
+				}
+				resp := result.Val.(*Response)
+				if !result.Shared {
+					return resp, nil
+				}
+				logging.FromContext(ctx).DebugContext(ctx, "shared in-flight upstream request", "url", RedactURL(req.URL))
+				return resp.clone(req.Provider)
+			}
+		}
+	}
+}
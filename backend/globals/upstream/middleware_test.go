@@ -0,0 +1,113 @@
+package upstream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/db"
+	"sports_api/logging"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLoggingTagsRequestAndRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+	ctx = logging.WithRequestID(ctx, "req-1")
+	c := NewClient("Odds", server.URL+"/", db.NewMemoryCache(0), nil)
+	params := map[string]string{"apiKey": "secret", "sport": "nba"}
+
+	for _, wantCache := range []string{"miss", "hit"} {
+		buf.Reset()
+		if _, err := c.Get(ctx, "events", params, "", nil); err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+		var record map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("log %q is not a single JSON record: %v", buf.String(), err)
+		}
+		want := map[string]any{
+			"msg": "upstream request", "request_id": "req-1", "provider": "Odds",
+			"host": strings.TrimPrefix(server.URL, "http://"), "endpoint": "events",
+			"status": float64(http.StatusOK), "cache": wantCache,
+		}
+		for k, v := range want {
+			if record[k] != v {
+				t.Errorf("%s = %v, want %v", k, record[k], v)
+			}
+		}
+		if _, ok := record["duration"]; !ok {
+			t.Error("record has no duration")
+		}
+		if strings.Contains(buf.String(), "secret") {
+			t.Errorf("log leaks the apiKey: %s", buf.String())
+		}
+	}
+
+	// Transport errors quote the URL, which must be redacted too
+	server.Close()
+	buf.Reset()
+	c = NewClient("Odds", server.URL+"/", nil, nil)
+	c.RetryPolicy = NoRetry
+	_, err := c.Get(ctx, "events", params, "", nil)
+	if err == nil || strings.Contains(err.Error(), "secret") || strings.Contains(buf.String(), "secret") {
+		t.Errorf("failed request leaks the apiKey: error %v, log %s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "upstream request failed") {
+		t.Errorf("failure not logged: %s", buf.String())
+	}
+}
+
+func TestTracingSpansCacheLookupAndAttempts(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("NHL", server.URL+"/", db.NewMemoryCache(0), nil)
+	for range 2 {
+		if _, err := c.Get(context.Background(), "v1/gamecenter/2024020001/boxscore", nil, "", nil); err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+	}
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		name := span.Name()
+		for _, attr := range span.Attributes() {
+			if attr.Key == "cache.result" {
+				name += " " + attr.Value.AsString()
+			}
+		}
+		names = append(names, name)
+	}
+	want := []string{
+		"cache lookup miss", "HTTP GET", "NHL v1/gamecenter/:id/boxscore",
+		"cache lookup hit", "NHL v1/gamecenter/:id/boxscore",
+	}
+	if strings.Join(names, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("spans = %v, want %v", names, want)
+	}
+	spans := recorder.Ended()
+	if spans[1].Parent().SpanID() != spans[2].SpanContext().SpanID() {
+		t.Error("attempt span is not a child of the request span")
+	}
+}
@@ -0,0 +1,78 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorderRecordsAndReplays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-requests-remaining", "42")
+		fmt.Fprintf(w, `{"sport": %q}`, r.URL.Query().Get("sport"))
+	}))
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	recorder := &Recorder{Mode: RecordRecord, Dir: dir}
+	c := newTestClient(server.URL + "/")
+	c.HTTPClient.Transport = recorder
+	if _, err := c.Get(ctx, "events", map[string]string{"sport": "nba", "apiKey": "secret"}, "", nil); err != nil {
+		t.Fatalf("recording Get returned error: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("recorded %d cassettes, want 1", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret") || !strings.Contains(string(data), "apiKey=REDACTED") {
+		t.Errorf("cassette does not scrub apiKey:\n%s", data)
+	}
+
+	// Replay without the server, and with a different key
+	server.Close()
+	recorder.Mode = RecordReplay
+	resp, err := c.Get(ctx, "events", map[string]string{"sport": "nba", "apiKey": "other"}, "", nil)
+	if err != nil {
+		t.Fatalf("replaying Get returned error: %v", err)
+	}
+	if resp.Data.(map[string]interface{})["sport"] != "nba" || resp.Headers.Get("x-requests-remaining") != "42" {
+		t.Errorf("replayed response = %+v, want recorded body and headers", resp)
+	}
+
+	if _, err := c.Get(ctx, "events", map[string]string{"sport": "nhl"}, "", nil); !errors.Is(err, ErrNoCassette) {
+		t.Errorf("Get of unrecorded request error = %v, want ErrNoCassette", err)
+	}
+}
+
+func TestSetRecorderCoversNewClients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	SetRecorder(&Recorder{Mode: RecordRecord, Dir: dir})
+	t.Cleanup(func() { SetRecorder(nil) })
+	if _, err := newTestClient(server.URL+"/").Get(ctx, "events", nil, "", nil); err != nil {
+		t.Fatalf("recording Get returned error: %v", err)
+	}
+
+	// A client built after the recorder was set, as Configure does, replays
+	server.Close()
+	SetRecorder(&Recorder{Mode: RecordReplay, Dir: dir})
+	if _, err := newTestClient(server.URL+"/").Get(ctx, "events", nil, "", nil); err != nil {
+		t.Errorf("replaying Get on a new client returned error: %v", err)
+	}
+	if _, err := newTestClient(server.URL+"/").Get(ctx, "scores", nil, "", nil); !errors.Is(err, ErrNoCassette) {
+		t.Errorf("Get of unrecorded request error = %v, want ErrNoCassette", err)
+	}
+}
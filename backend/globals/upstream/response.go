@@ -0,0 +1,95 @@
+package upstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sports_api/apierror"
+	"strings"
+)
+
+// Response represents the API response structure shared by every provider.
+type Response struct {
+	Status     string
+	StatusCode int
+	Data       interface{}
+	URL        string
+	Headers    http.Header
+	Stale      bool        // Served from an expired cache entry because the upstream was unavailable
+	Cache      CacheStatus // How the Cache middleware answered; empty when it was not involved
+	Raw        []byte      // Body exactly as received, still encoded per the Content-Encoding header
+}
+
+// CacheStatus is how the Cache middleware answered a request.
+type CacheStatus string
+
+const (
+	CacheHit   CacheStatus = "hit"   // Served a fresh cached response
+	CacheMiss  CacheStatus = "miss"  // Fetched from the upstream and stored
+	CacheStale CacheStatus = "stale" // Served an expired cached response
+)
+
+// GetData returns the decoded response body.
+func (r *Response) GetData() (interface{}, error) {
+	if r.Data == nil {
+		return nil, apierror.New(apierror.Decode, "No Data")
+	}
+	return r.Data, nil
+}
+
+// clone returns a copy of r that shares nothing with it, decoding Data again
+// from Raw so that callers can modify it freely.
+func (r *Response) clone(provider string) (*Response, error) {
+	c := *r
+	c.Headers = r.Headers.Clone()
+	c.Raw = bytes.Clone(r.Raw)
+	c.Data = nil
+	if r.Data != nil {
+		if err := c.decode(provider); err != nil {
+			return nil, err
+		}
+	}
+	return &c, nil
+}
+
+// decode fills Data from Raw. Fresh and cached responses both go through here,
+// so numbers are always json.Number regardless of where the body came from.
+func (r *Response) decode(provider string) error {
+	data, err := DecodeBody(provider, r.Raw, r.Headers)
+	if err != nil {
+		return err
+	}
+	r.Data = data
+	return nil
+}
+
+// DecodeBody decompresses raw based on its Content-Encoding (gzip, deflate,
+// br or zstd) and decodes the JSON.
+func DecodeBody(provider string, raw []byte, header http.Header) (interface{}, error) {
+	reader, closeReaders, err := decodeContent(bytes.NewReader(raw), header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, apierror.New(apierror.Decode, "%s response: %w", provider, err)
+	}
+	defer closeReaders()
+
+	if strings.HasPrefix(header.Get("Content-Type"), "text/html") {
+		htmlContent, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HTML response: %w", err)
+		}
+		return string(htmlContent), apierror.New(apierror.Blocked, "%s API returned HTML error page: %s", provider, string(htmlContent))
+	}
+
+	// Decode JSON with UseNumber to avoid float precision issues
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, apierror.New(apierror.Decode, "failed to parse JSON: %w", err)
+	}
+
+	return result, nil
+}
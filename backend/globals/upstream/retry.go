@@ -0,0 +1,195 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sports_api/apierror"
+	"sports_api/logging"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed upstream calls are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; 1 disables retries
+	BaseDelay   time.Duration // Backoff before the first retry, doubled on every further attempt
+	MaxDelay    time.Duration // Upper bound on a single backoff or Retry-After wait
+	Jitter      float64       // Fraction of each backoff that is randomised, between 0 and 1
+
+	// RetryableStatus reports whether a non-200 status is worth retrying.
+	// DefaultRetryableStatus is used when nil.
+	RetryableStatus func(status int) bool
+}
+
+// DefaultRetryPolicy retries transient failures three times with jittered exponential backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.5,
+}
+
+// NoRetry issues each request exactly once.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// StatusError is returned when an upstream responds with a non-200 status.
+type StatusError struct {
+	StatusCode int
+	Header     http.Header
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response status: %d", e.StatusCode)
+}
+
+// Kind classifies the status: 400 and 422 mean the parameters we passed were
+// rejected, 401 and 403 that the upstream refused us, and 404 that the
+// resource does not exist. Anything else means the upstream is unavailable.
+func (e *StatusError) Kind() apierror.Kind {
+	switch e.StatusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return apierror.Validation
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return apierror.Blocked
+	case http.StatusNotFound:
+		return apierror.NotFound
+	}
+	return apierror.Unavailable
+}
+
+// Is matches the apierror.Kind of the status.
+func (e *StatusError) Is(target error) bool {
+	kind, ok := target.(apierror.Kind)
+	return ok && kind == e.Kind()
+}
+
+// RetryAfter returns the wait requested by the upstream's Retry-After header, or zero.
+func (e *StatusError) RetryAfter() time.Duration {
+	return parseRetryAfter(e.Header.Get("Retry-After"), time.Now())
+}
+
+// DefaultRetryableStatus retries rate limiting, request timeouts and 5xx gateway errors.
+func DefaultRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// RetryPolicyFor returns the policy for endpoint, preferring the most specific
+// matching EndpointRetryPolicies pattern over the client-wide RetryPolicy.
+func (c *Client) RetryPolicyFor(endpoint string) RetryPolicy {
+	if policy, ok := matchPattern(c.EndpointRetryPolicies, endpoint); ok {
+		return policy
+	}
+	return c.RetryPolicy
+}
+
+// Retry re-issues failed requests using the policy policyFor selects for each endpoint.
+func Retry(policyFor func(endpoint string) RetryPolicy) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			policy := policyFor(req.Endpoint)
+
+			for attempt := 1; ; attempt++ {
+				resp, err := next(ctx, req)
+				if err == nil || attempt >= policy.MaxAttempts || !policy.retryable(ctx, err) {
+					return resp, err
+				}
+
+				delay, ok := policy.delay(attempt, err)
+				if !ok {
+					return nil, err
+				}
+				if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < delay {
+					return nil, err
+				}
+
+				logging.FromContext(ctx).InfoContext(ctx, "retrying upstream request",
+					"url", RedactURL(req.URL), "delay", delay, "attempt", attempt+1, "max_attempts", policy.MaxAttempts, "error", err)
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+	}
+}
+
+// retryable classifies err as a transient failure worth another attempt.
+func (p RetryPolicy) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if p.RetryableStatus != nil {
+			return p.RetryableStatus(statusErr.StatusCode)
+		}
+		return DefaultRetryableStatus(statusErr.StatusCode)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// delay returns the wait before the next attempt. A Retry-After beyond MaxDelay
+// means the upstream will not recover in time, so ok is false.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff < p.BaseDelay || (p.MaxDelay > 0 && backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+	if p.Jitter > 0 && backoff > 0 {
+		backoff -= time.Duration(rand.Float64() * p.Jitter * float64(backoff))
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if retryAfter := statusErr.RetryAfter(); retryAfter > 0 {
+			if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+				return 0, false
+			}
+			if retryAfter > backoff {
+				backoff = retryAfter
+			}
+		}
+	}
+	return backoff, true
+}
+
+// parseRetryAfter handles both the delay-seconds and HTTP-date forms of Retry-After.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}
+
+func newRetryClient(baseURL string, policy RetryPolicy) *Client {
+	c := newTestClient(baseURL)
+	c.RetryPolicy = policy
+	c.Use(Retry(c.RetryPolicyFor))
+	return c
+}
+
+func TestRetryRecoversFromTransientStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	if _, err := newRetryClient(server.URL+"/", fastRetry).Get(context.Background(), "endpoint", nil, "", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetrySkipsPermanentStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := newRetryClient(server.URL+"/", fastRetry).Get(context.Background(), "endpoint", nil, "", nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Get error = %v, want 404 StatusError", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	if _, err := newRetryClient(server.URL+"/", fastRetry).Get(context.Background(), "endpoint", nil, "", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", elapsed)
+	}
+
+	// A Retry-After longer than MaxDelay gives up immediately
+	atomic.StoreInt32(&calls, 0)
+	short := fastRetry
+	short.MaxDelay = 100 * time.Millisecond
+	if _, err := newRetryClient(server.URL+"/", short).Get(context.Background(), "endpoint", nil, "", nil); err == nil {
+		t.Errorf("Expected error when Retry-After exceeds MaxDelay")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetryEndpointOverride(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := newRetryClient(server.URL, fastRetry)
+	c.EndpointRetryPolicies = map[string]RetryPolicy{"/*/events/*/odds": NoRetry}
+
+	_, _ = c.Get(context.Background(), "/basketball_nba/events/abc/odds", nil, "", nil)
+	if calls != 1 {
+		t.Errorf("calls with NoRetry override = %d, want 1", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	_, _ = c.Get(context.Background(), "/basketball_nba/events", nil, "", nil)
+	if calls != 3 {
+		t.Errorf("calls with default policy = %d, want 3", calls)
+	}
+}
+
+func TestRetryPolicyForOverlappingPatterns(t *testing.T) {
+	c := newRetryClient("https://api.the-odds-api.com/v4/sports", fastRetry)
+	c.EndpointRetryPolicies = map[string]RetryPolicy{
+		"/*/events/*/odds":              NoRetry,
+		"/*/events/*":                   {MaxAttempts: 2},
+		"/basketball_nba/events/*/odds": {MaxAttempts: 5},
+	}
+
+	tests := []struct {
+		endpoint string
+		want     int
+	}{
+		{"/icehockey_nhl/events/abc/odds", NoRetry.MaxAttempts},
+		{"/icehockey_nhl/events/abc", 2},
+		{"/basketball_nba/events/abc/odds", 5},
+		{"/basketball_nba/events", fastRetry.MaxAttempts},
+	}
+	for range 20 {
+		for _, test := range tests {
+			if got := c.RetryPolicyFor(test.endpoint).MaxAttempts; got != test.want {
+				t.Fatalf("RetryPolicyFor(%q).MaxAttempts = %d, want %d", test.endpoint, got, test.want)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-1", 0},
+		{"Wed, 01 Jan 2025 12:00:30 GMT", 30 * time.Second},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, test := range tests {
+		if got := parseRetryAfter(test.value, now); got != test.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, expected := range want {
+		if got, _ := policy.delay(i+1, errors.New("boom")); got != expected {
+			t.Errorf("delay(attempt %d) = %s, want %s", i+1, got, expected)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got, _ := policy.delay(1, errors.New("boom")); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("jittered delay = %s, want between 50ms and 100ms", got)
+		}
+	}
+}
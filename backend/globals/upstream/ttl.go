@@ -0,0 +1,52 @@
+package upstream
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// Cache lifetimes by how quickly upstream data changes
+const (
+	LiveTTL       = 30 * time.Second    // Scoreboards and in-progress games
+	OddsTTL       = 5 * time.Minute     // Lines and player props
+	SeasonTTL     = 6 * time.Hour       // Game logs and season aggregates
+	RosterTTL     = 12 * time.Hour      // Player lists, rosters and bios
+	HistoricalTTL = 14 * 24 * time.Hour // Drafts, franchise history and other settled data
+)
+
+// DefaultCacheTTL applies to endpoints without a more specific rule.
+const DefaultCacheTTL = 24 * time.Hour
+
+// CacheTTLFor returns how long responses from endpoint are cached, checking
+// CacheTTLOverrides, then EndpointCacheTTLs, then the client-wide CacheTTL.
+// Within each, the most specific matching pattern wins. A zero TTL disables
+// caching for the endpoint.
+func (c *Client) CacheTTLFor(endpoint string) time.Duration {
+	if ttl, ok := matchPattern(c.CacheTTLOverrides, endpoint); ok {
+		return ttl
+	}
+	if ttl, ok := matchPattern(c.EndpointCacheTTLs, endpoint); ok {
+		return ttl
+	}
+	return c.CacheTTL
+}
+
+// matchPattern returns the value of the most specific path.Match pattern in
+// patterns that matches endpoint: the one with the most literal characters,
+// ties going to the lexically smaller pattern. Overlapping patterns therefore
+// resolve the same way on every call, whatever the map's iteration order.
+func matchPattern[V any](patterns map[string]V, endpoint string) (V, bool) {
+	var best V
+	bestPattern, bestLiterals, found := "", -1, false
+	for pattern, value := range patterns {
+		if matched, _ := path.Match(pattern, endpoint); !matched {
+			continue
+		}
+		literals := len(pattern) - strings.Count(pattern, "*") - strings.Count(pattern, "?")
+		if literals > bestLiterals || literals == bestLiterals && pattern < bestPattern {
+			best, bestPattern, bestLiterals, found = value, pattern, literals, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,39 @@
+package upstream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheTTLFor(t *testing.T) {
+	c := newTestClient("https://api.the-odds-api.com/v4/sports")
+	c.EndpointCacheTTLs = map[string]time.Duration{
+		"scoreboardv2":                  LiveTTL,
+		"/*/events/*/odds":              OddsTTL,
+		"/*/events/*":                   SeasonTTL,
+		"/basketball_nba/events/*/odds": time.Minute,
+		"/*/events/?bc/odds":            RosterTTL,
+	}
+	c.CacheTTLOverrides = map[string]time.Duration{"drafthistory": time.Hour}
+
+	tests := []struct {
+		endpoint string
+		want     time.Duration
+	}{
+		{"scoreboardv2", LiveTTL},
+		{"/icehockey_nhl/events/xyz/odds", OddsTTL},
+		{"/icehockey_nhl/events/xyz", SeasonTTL},
+		{"/basketball_nba/events/xyz/odds", time.Minute},
+		{"/icehockey_nhl/events/abc/odds", RosterTTL},
+		{"/basketball_nba/events", DefaultCacheTTL},
+		{"drafthistory", time.Hour},
+	}
+	// Overlapping patterns resolve to the most specific one on every call
+	for range 20 {
+		for _, test := range tests {
+			if got := c.CacheTTLFor(test.endpoint); got != test.want {
+				t.Fatalf("CacheTTLFor(%q) = %s, want %s", test.endpoint, got, test.want)
+			}
+		}
+	}
+}
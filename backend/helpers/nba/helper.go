@@ -1,8 +1,8 @@
 package nba
 
 import (
-	"errors"
 	"regexp"
+	"sports_api/apierror"
 	"strconv"
 	"time"
 )
@@ -44,7 +44,7 @@ func ParseDateString(dateStr string) (*time.Time, error) {
 
 	parsedDate, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		return nil, errors.New("invalid date format: must be 'YYYY-MM-DD'")
+		return nil, apierror.New(apierror.Validation, "invalid date format: must be 'YYYY-MM-DD'")
 	}
 	return &parsedDate, nil
 }
@@ -52,7 +52,7 @@ func ParseDateString(dateStr string) (*time.Time, error) {
 // ValidateLeagueID checks if the given LeagueID is valid.
 func ValidateLeagueID(leagueID string) (bool, error) {
 	if _, exists := validLeagueIDs[leagueID]; !exists {
-		return false, errors.New("invalid LeagueID: must be '00' (NBA), '10' (WNBA), or '20' (G-League')")
+		return false, apierror.New(apierror.Validation, "invalid LeagueID: must be '00' (NBA), '10' (WNBA), or '20' (G-League')")
 	}
 	return true, nil
 }
@@ -60,7 +60,7 @@ func ValidateLeagueID(leagueID string) (bool, error) {
 // ValidateSeason checks if the given Season format is valid using regex.
 func ValidateSeason(season string) (bool, error) {
 	if !validSeasonYearOrAllTime.MatchString(season) {
-		return false, errors.New("invalid Season format: must be 'YYYY-YY' (e.g., '2023-24')")
+		return false, apierror.New(apierror.Validation, "invalid Season format: must be 'YYYY-YY' (e.g., '2023-24')")
 	}
 	return true, nil
 }
@@ -68,7 +68,7 @@ func ValidateSeason(season string) (bool, error) {
 // ValidateSeasonType checks if the given season type is valid.
 func ValidateSeasonType(seasonType string) (bool, error) {
 	if !validSeasonTypes.MatchString(seasonType) {
-		return false, errors.New("invalid SeasonType: must be 'Regular Season', 'Pre Season', 'Playoffs', or 'All Star'")
+		return false, apierror.New(apierror.Validation, "invalid SeasonType: must be 'Regular Season', 'Pre Season', 'Playoffs', or 'All Star'")
 	}
 	return true, nil
 }
@@ -76,7 +76,7 @@ func ValidateSeasonType(seasonType string) (bool, error) {
 // ValidatePerMode checks if the given perMode is valid.
 func ValidatePerMode(perMode string) (bool, error) {
 	if !validPerMode.MatchString(perMode) {
-		return false, errors.New("invalid PerMode: must be 'Totals' or 'PerGame'")
+		return false, apierror.New(apierror.Validation, "invalid PerMode: must be 'Totals' or 'PerGame'")
 	}
 	return true, nil
 }
@@ -84,7 +84,7 @@ func ValidatePerMode(perMode string) (bool, error) {
 // ValidateLocation checks if the given Location is valid.
 func ValidateLocation(location string) (bool, error) {
 	if !validLocations.MatchString(location) {
-		return false, errors.New("invalid Location: must be 'Home' or 'Road'")
+		return false, apierror.New(apierror.Validation, "invalid Location: must be 'Home' or 'Road'")
 	}
 	return true, nil
 }
@@ -92,7 +92,7 @@ func ValidateLocation(location string) (bool, error) {
 // ValidateConference checks if the given Conference is valid.
 func ValidateConference(conference string) (bool, error) {
 	if !validConferences.MatchString(conference) {
-		return false, errors.New("invalid Conference: must be 'East' or 'West'")
+		return false, apierror.New(apierror.Validation, "invalid Conference: must be 'East' or 'West'")
 	}
 	return true, nil
 }
@@ -100,7 +100,7 @@ func ValidateConference(conference string) (bool, error) {
 // ValidateDivision checks if the given Division is valid.
 func ValidateDivision(division string) (bool, error) {
 	if !validDivisions.MatchString(division) {
-		return false, errors.New("invalid Division: must be a valid NBA division")
+		return false, apierror.New(apierror.Validation, "invalid Division: must be a valid NBA division")
 	}
 	return true, nil
 }
@@ -108,7 +108,7 @@ func ValidateDivision(division string) (bool, error) {
 // ValidateStarterBench checks if the given StarterBench is valid.
 func ValidateStarterBench(starterBench string) (bool, error) {
 	if !validStarterBench.MatchString(starterBench) {
-		return false, errors.New("invalid StarterBench: must be 'Starters' or 'Bench'")
+		return false, apierror.New(apierror.Validation, "invalid StarterBench: must be 'Starters' or 'Bench'")
 	}
 	return true, nil
 }
@@ -116,7 +116,7 @@ func ValidateStarterBench(starterBench string) (bool, error) {
 // ValidateOutcome checks if the given Outcome is valid.
 func ValidateOutcome(outcome string) (bool, error) {
 	if !(outcome == "W" || outcome == "L") {
-		return false, errors.New("invalid Outcome: must be 'W' or 'L'")
+		return false, apierror.New(apierror.Validation, "invalid Outcome: must be 'W' or 'L'")
 	}
 	return true, nil
 }
@@ -124,7 +124,7 @@ func ValidateOutcome(outcome string) (bool, error) {
 // ValidateSeasonSegment checks if the given SeasonSegment is valid.
 func ValidateSeasonSegment(seasonSegment string) (bool, error) {
 	if !validSeasonSegment.MatchString(seasonSegment) {
-		return false, errors.New("invalid SeasonSegment: must be 'Post All-Star' or 'Pre All-Star'")
+		return false, apierror.New(apierror.Validation, "invalid SeasonSegment: must be 'Post All-Star' or 'Pre All-Star'")
 	}
 	return true, nil
 }
@@ -132,7 +132,7 @@ func ValidateSeasonSegment(seasonSegment string) (bool, error) {
 // ValidatePlayerPosition checks if the given PlayerPosition is valid.
 func ValidatePlayerPosition(playerPosition string) (bool, error) {
 	if !validPlayerPosition.MatchString(playerPosition) {
-		return false, errors.New("invalid PlayerPosition: must be 'F', 'C', 'G', 'C-F', 'F-C', 'F-G', or 'G-F'")
+		return false, apierror.New(apierror.Validation, "invalid PlayerPosition: must be 'F', 'C', 'G', 'C-F', 'F-C', 'F-G', or 'G-F'")
 	}
 	return true, nil
 }
@@ -140,7 +140,7 @@ func ValidatePlayerPosition(playerPosition string) (bool, error) {
 // ValidatePlayerExperience checks if the given PlayerExperience is valid.
 func ValidatePlayerExperience(playerExperience string) (bool, error) {
 	if !validPlayerExperience.MatchString(playerExperience) {
-		return false, errors.New("invalid PlayerExperience: must be 'Rookie', 'Sophomore', or 'Veteran'")
+		return false, apierror.New(apierror.Validation, "invalid PlayerExperience: must be 'Rookie', 'Sophomore', or 'Veteran'")
 	}
 	return true, nil
 }
@@ -148,7 +148,7 @@ func ValidatePlayerExperience(playerExperience string) (bool, error) {
 // ValidateGameScope checks if the given GameScope is valid.
 func ValidateGameScope(gameScope string) (bool, error) {
 	if !validGameScope.MatchString(gameScope) {
-		return false, errors.New("invalid GameScope: must be 'Yesterday' or 'Last 10'")
+		return false, apierror.New(apierror.Validation, "invalid GameScope: must be 'Yesterday' or 'Last 10'")
 	}
 	return true, nil
 }
@@ -156,7 +156,7 @@ func ValidateGameScope(gameScope string) (bool, error) {
 // IsPositive checks if the given integer is positive and returns an error if invalid.
 func IsPositive(x int) (bool, error) {
 	if x <= 0 {
-		return false, errors.New("invalid value: must be a positive integer")
+		return false, apierror.New(apierror.Validation, "invalid value: must be a positive integer")
 	}
 	return true, nil
 }
@@ -164,7 +164,7 @@ func IsPositive(x int) (bool, error) {
 // ValidatePlayerID ensures the playerID is not empty.
 func ValidatePlayerID(playerID string) (bool, error) {
 	if playerID == "" {
-		return false, errors.New("PlayerID is required and cannot be empty")
+		return false, apierror.New(apierror.Validation, "PlayerID is required and cannot be empty")
 	}
 	return true, nil
 }
@@ -172,7 +172,7 @@ func ValidatePlayerID(playerID string) (bool, error) {
 // ValidateTeamID checks if the given TeamID is not empty.
 func ValidateTeamID(teamID string) (bool, error) {
 	if teamID == "" {
-		return false, errors.New("TeamID is required and cannot be empty")
+		return false, apierror.New(apierror.Validation, "TeamID is required and cannot be empty")
 	}
 	return true, nil
 }
@@ -180,7 +180,7 @@ func ValidateTeamID(teamID string) (bool, error) {
 // ValidatePlayerScope checks if the given PlayerScope is valid.
 func ValidatePlayerScope(playerScope string) (bool, error) {
 	if !validPlayerScope.MatchString(playerScope) {
-		return false, errors.New("invalid PlayerScope: must be 'All Players' or 'Rookies'")
+		return false, apierror.New(apierror.Validation, "invalid PlayerScope: must be 'All Players' or 'Rookies'")
 	}
 	return true, nil
 }
@@ -188,7 +188,7 @@ func ValidatePlayerScope(playerScope string) (bool, error) {
 // ValidateGameIDs checks if the given GameIDs string is in a valid format.
 func ValidateGameIDs(gameIDs string) (bool, error) {
 	if !validGameIDPattern.MatchString(gameIDs) {
-		return false, errors.New("invalid GameIDs format: must be a 10-digit GameID or multiple GameIDs separated by commas")
+		return false, apierror.New(apierror.Validation, "invalid GameIDs format: must be a 10-digit GameID or multiple GameIDs separated by commas")
 	}
 	return true, nil
 }
@@ -196,7 +196,7 @@ func ValidateGameIDs(gameIDs string) (bool, error) {
 // ValidateGameID ensures the GameID follows the correct format (10-digit numeric string).
 func ValidateGameID(gameID string) (bool, error) {
 	if !validGameIDPattern.MatchString(gameID) {
-		return false, errors.New("invalid GameID: must be a 10-digit number (e.g., '0021700807')")
+		return false, apierror.New(apierror.Validation, "invalid GameID: must be a 10-digit number (e.g., '0021700807')")
 	}
 	return true, nil
 }
@@ -204,7 +204,7 @@ func ValidateGameID(gameID string) (bool, error) {
 // ValidatePlayerOrTeam checks if the given value is 'Player' or 'Team'.
 func ValidatePlayerOrTeam(playerOrTeam string) (bool, error) {
 	if !validPlayerOrTeam.MatchString(playerOrTeam) {
-		return false, errors.New("invalid PlayerOrTeam: must be 'Player' or 'Team'")
+		return false, apierror.New(apierror.Validation, "invalid PlayerOrTeam: must be 'Player' or 'Team'")
 	}
 	return true, nil
 }
@@ -212,14 +212,14 @@ func ValidatePlayerOrTeam(playerOrTeam string) (bool, error) {
 // ValidateSeasonYear checks if the given season year is valid.
 func ValidateSeasonYear(seasonYear string) (bool, error) {
 	if !validSeasonYear.MatchString(seasonYear) {
-		return false, errors.New("invalid SeasonYear: must be a four-digit year (e.g., '2019')")
+		return false, apierror.New(apierror.Validation, "invalid SeasonYear: must be a four-digit year (e.g., '2019')")
 	}
 	return true, nil
 }
 
 func ValidateMeasureType(measureType string) (bool, error) {
 	if _, ok := validMeasureTypes[measureType]; !ok {
-		return false, errors.New("invalid measure type")
+		return false, apierror.New(apierror.Validation, "invalid measure type")
 	}
 	return true, nil
 
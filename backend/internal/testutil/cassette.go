@@ -0,0 +1,42 @@
+// Package testutil holds helpers shared by tests across packages.
+package testutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sports_api/globals/upstream"
+	"testing"
+)
+
+// ModeEnv selects how Cassette treats upstream requests: replay (the default),
+// record or off. Only record and off reach the network.
+const ModeEnv = "CASSETTE_MODE"
+
+// CassetteDir holds each test's cassettes, relative to the package under test.
+const CassetteDir = "testdata/cassettes"
+
+// Cassette sends the upstream requests t makes through a Recorder using
+// CassetteDir/<test name>. In replay mode a test with no recorded cassettes
+// fails, as does a request missing from them, with upstream.ErrNoCassette.
+// Run with CASSETTE_MODE=record to record them and commit the result.
+func Cassette(t *testing.T) {
+	t.Helper()
+	mode := upstream.RecordReplay
+	if value, ok := os.LookupEnv(ModeEnv); ok {
+		parsed, err := upstream.ParseRecordMode(value)
+		if err != nil {
+			t.Fatalf("%s: %v", ModeEnv, err)
+		}
+		mode = parsed
+	}
+
+	dir := filepath.Join(CassetteDir, t.Name())
+	if mode == upstream.RecordReplay {
+		if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("no cassettes recorded in %s; run with %s=record to record them", dir, ModeEnv)
+		}
+	}
+	upstream.SetRecorder(&upstream.Recorder{Mode: mode, Dir: dir})
+	t.Cleanup(func() { upstream.SetRecorder(nil) })
+}
@@ -0,0 +1,51 @@
+package testutil
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/config"
+	"strings"
+	"testing"
+)
+
+// FakeOddsEvent is the ID of the one event the fake Odds API lists for every sport.
+const FakeOddsEvent = "fake-event-1"
+
+// FakeOddsAPI starts a fake Odds API that lives as long as t and returns a
+// default configuration pointed at it with a test key. It lists the sports,
+// one event per sport and one player prop on that event, and reports quota
+// headers like the real API. Requests without an apiKey fail with 401.
+func FakeOddsAPI(t *testing.T) *config.Config {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apiKey") == "" {
+			http.Error(w, `{"message": "missing apiKey"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-requests-remaining", "499")
+		w.Header().Set("x-requests-used", "1")
+
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v4/sports"), "/"), "/")
+		switch {
+		case len(parts) == 1 && parts[0] == "":
+			fmt.Fprint(w, `[{"key": "basketball_nba", "group": "Basketball", "title": "NBA", "active": true}]`)
+		case len(parts) == 2 && parts[1] == "events":
+			fmt.Fprintf(w, `[{"id": %q, "sport_key": %q, "sport_title": "Fake", "commence_time": "2024-03-28T23:00:00Z", "home_team": "Home Team", "away_team": "Away Team"}]`, FakeOddsEvent, parts[0])
+		case len(parts) == 4 && parts[1] == "events" && parts[3] == "odds":
+			fmt.Fprintf(w, `{"id": %q, "sport_key": %q, "sport_title": "Fake", "commence_time": "2024-03-28T23:00:00Z", "home_team": "Home Team", "away_team": "Away Team",
+				"bookmakers": [{"key": "fanduel", "title": "FanDuel", "markets": [{"key": "player_points", "last_update": "2024-03-28T20:00:00Z",
+					"outcomes": [{"name": "Over", "description": "Home Player", "point": 24.5, "price": -110}]}]}]}`, parts[2], parts[0])
+		default:
+			t.Errorf("fake Odds API got %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := config.Default()
+	cfg.Odds.BaseURL = server.URL + "/v4/sports"
+	cfg.Odds.APIKey = "test-key-0001"
+	return cfg
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sports_api/config"
+	"strings"
+	"testing"
+)
+
+func TestNewHonoursLevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.Log{Level: "warn", Format: "json"}, &buf)
+	logger.Info("dropped")
+	logger.Warn("kept", "n", 1)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("logged %d lines, want only the warning:\n%s", len(lines), buf.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("record is not JSON: %v", err)
+	}
+	if record["msg"] != "kept" || record["level"] != "WARN" {
+		t.Errorf("record = %v, want the warning", record)
+	}
+}
+
+func TestRequestIDTagsContextLogger(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("FromContext without a logger is not slog.Default")
+	}
+
+	var buf bytes.Buffer
+	ctx := NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx = WithRequestID(ctx, "abc123")
+	FromContext(ctx).Info("upstream request")
+
+	if RequestID(ctx) != "abc123" {
+		t.Errorf("RequestID = %q, want abc123", RequestID(ctx))
+	}
+	if !strings.Contains(buf.String(), "request_id=abc123") {
+		t.Errorf("log %q is not tagged with the request ID", buf.String())
+	}
+	if id := NewRequestID(); len(id) != 16 || id == NewRequestID() {
+		t.Errorf("NewRequestID = %q, want a random 16 character ID", id)
+	}
+}
@@ -1,13 +1,121 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sports_api/background"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/globals/odds"
+	"sports_api/globals/providers"
+	"sports_api/globals/upstream"
+	"sports_api/logging"
+	"sports_api/metrics"
 	"sports_api/router"
+	"sports_api/tracing"
+	"syscall"
+	"time"
 )
 
+// startupPingTimeout bounds the cache check made before the server starts listening.
+const startupPingTimeout = 5 * time.Second
+
 func main() {
-	r := router.SetupRouter() // Initialize the main router
-	err := r.Run(":8080")
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	flag.Parse()
+
+	if err := run(*configPath); err != nil {
+		slog.Error("server exited", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run serves the API until SIGINT or SIGTERM, then drains in-flight requests
+// and background work before releasing the cache and trace exporter.
+func run(configPath string) (err error) {
+	cfg, err := config.Load(configPath)
 	if err != nil {
-		return
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	slog.SetDefault(logging.New(cfg.Log, os.Stderr))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to set up %s tracing: %w", cfg.Tracing.Exporter, err)
+	}
+	defer func() {
+		if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to flush traces: %w", shutdownErr))
+		}
+	}()
+
+	cache, err := db.NewCache(cfg.Cache)
+	if err != nil {
+		return fmt.Errorf("failed to create %s cache: %w", cfg.Cache.Backend, err)
+	}
+	defer func() {
+		if closeErr := cache.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to close cache: %w", closeErr))
+		}
+	}()
+	pingCtx, cancel := context.WithTimeout(ctx, startupPingTimeout)
+	err = cache.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("%s cache is unreachable: %w", cfg.Cache.Backend, err)
+	}
+
+	upstream.Configure(cfg)
+	clients := providers.New(cfg, cache)
+	metrics.Registry.MustRegister(odds.NewQuotaCollector(clients.Odds))
+
+	listener, err := net.Listen("tcp", cfg.Server.Addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:           router.SetupRouter(cfg, cache, clients),
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	slog.Info("listening", "addr", listener.Addr().String())
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop() // A second signal kills the process instead of waiting for the drain
+	slog.Info("shutting down", "timeout", cfg.Server.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	var errs []error
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain requests: %w", err))
+		server.Close()
+	}
+	if err := background.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain background work: %w", err))
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		errs = append(errs, err)
 	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,81 @@
+// Package metrics holds the Prometheus registry every package records to and
+// the metrics shared between them. Packages with state of their own, such as
+// the circuit breaker and the odds quota, register collectors here too.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes every metric name.
+const Namespace = "sports_api"
+
+// Registry is served at /metrics. It is separate from the global default
+// registry so only the API's own metrics and the runtime collectors appear.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+var (
+	// UpstreamDuration is the time from sending an upstream request to reading
+	// its body, per attempt, excluding time spent waiting for the rate limiter.
+	UpstreamDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "upstream",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of upstream requests by provider and endpoint.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30},
+	}, []string{"provider", "endpoint"})
+
+	// UpstreamResponses counts upstream attempts by status code, or "error"
+	// when no response arrived.
+	UpstreamResponses = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "upstream",
+		Name:      "responses_total",
+		Help:      "Upstream responses by provider and status code.",
+	}, []string{"provider", "status"})
+
+	// CacheRequests counts cache lookups, each exactly once, by result: hit,
+	// stale, miss or error.
+	CacheRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Response cache lookups by provider and result.",
+	}, []string{"provider", "result"})
+
+	// CacheWriteErrors counts responses that could not be stored in the cache.
+	CacheWriteErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "cache",
+		Name:      "write_errors_total",
+		Help:      "Responses that failed to be encoded or stored in the cache, by provider.",
+	}, []string{"provider"})
+
+	// HTTPDuration is the latency of the API's own routes.
+	HTTPDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of API requests by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Handler serves Registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
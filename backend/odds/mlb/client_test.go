@@ -0,0 +1,14 @@
+package mlb
+
+import (
+	"sports_api/globals/odds"
+	"sports_api/internal/testutil"
+	"testing"
+)
+
+// newTestClient returns an Odds API client with the default configuration,
+// pointed at a fake Odds API that lives as long as t.
+func newTestClient(t *testing.T) *odds.Client {
+	t.Helper()
+	return odds.NewOddsApiClient(testutil.FakeOddsAPI(t), nil)
+}
@@ -1,10 +1,13 @@
 package mlb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"sports_api/globals/odds"
+	"sports_api/logging"
+	"sports_api/tracing"
 	"time"
 )
 
@@ -19,15 +22,15 @@ type OddsEvent struct {
 
 var sportsEventsURL = "baseball_mlb_preseason"
 
-func GetAllMLBEvents() (*odds.OddsApiResponse, error) {
+func GetAllMLBEvents(ctx context.Context, oddsClient *odds.Client) (*odds.OddsApiResponse, error) {
 
-	eventsURL := odds.GlobalOddsClient.GetSportEventsURL(sportsEventsURL)
+	eventsURL := oddsClient.GetSportEventsURL(sportsEventsURL)
 
 	// No query parameters needed for this endpoint
 	params := map[string]string{}
 
 	// Make API request
-	response, err := odds.GlobalOddsClient.GetOddsRequest(eventsURL, params, nil)
+	response, err := oddsClient.GetOddsRequest(ctx, eventsURL, params, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch events for sport %s: %w", "nba", err)
 	}
@@ -36,9 +39,9 @@ func GetAllMLBEvents() (*odds.OddsApiResponse, error) {
 }
 
 // GetParsedNBAEvents calls GetAllNBAEvents and unmarshals the data into a slice of OddsEvent
-func GetandUnmarshallAllMLBEvents() ([]OddsEvent, error) {
+func GetandUnmarshallAllMLBEvents(ctx context.Context, oddsClient *odds.Client) ([]OddsEvent, error) {
 	// Fetch NBA events
-	response, err := GetAllMLBEvents()
+	response, err := GetAllMLBEvents(ctx, oddsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +62,12 @@ func GetandUnmarshallAllMLBEvents() ([]OddsEvent, error) {
 }
 
 // GetPlayerProps fetches player props for all NBA events
-func GetPlayerProps() (MatchupOddsSlice, error) {
+func GetPlayerProps(ctx context.Context, oddsClient *odds.Client) (_ MatchupOddsSlice, err error) {
+	ctx, span := tracing.Start(ctx, "odds/mlb.GetPlayerProps")
+	defer tracing.End(span, &err)
+
 	// Get all NBA events
-	events, err := GetandUnmarshallAllMLBEvents()
+	events, err := GetandUnmarshallAllMLBEvents(ctx, oddsClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get NBA events: %w", err)
 	}
@@ -70,9 +76,13 @@ func GetPlayerProps() (MatchupOddsSlice, error) {
 
 	// Loop through each event and fetch player props
 	for _, event := range events {
+		// Stop fetching once the caller has gone away
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
 		// Construct URL for player props using the event ID
-		playerPropsURL := odds.GlobalOddsClient.GetEventOddsURL(sportsEventsURL, event.Id)
+		playerPropsURL := oddsClient.GetEventOddsURL(sportsEventsURL, event.Id)
 
 		// No query parameters needed
 		params := map[string]string{"regions": "us",
@@ -84,16 +94,20 @@ func GetPlayerProps() (MatchupOddsSlice, error) {
 		//	"oddsFormat": "american"}
 
 		// Make API request for player props
-		response, err := odds.GlobalOddsClient.GetOddsRequest(playerPropsURL, params, nil)
+		response, err := oddsClient.GetOptionalOddsRequest(ctx, playerPropsURL, params, nil)
+		if errors.Is(err, odds.ErrBudgetExceeded) {
+			logging.FromContext(ctx).WarnContext(ctx, "skipping player props for the remaining events", "error", err)
+			break
+		}
 		if err != nil {
-			log.Printf("Failed to fetch player props for event %s: %v", event.Id, err)
+			logging.FromContext(ctx).WarnContext(ctx, "failed to fetch player props", "event", event.Id, "error", err)
 			continue // Skip this event and continue to the next one
 		}
 
 		var m MatchupOdds
 		responseBytes, _ := json.Marshal(response.Data)
 		if err := json.Unmarshal(responseBytes, &m); err != nil {
-			log.Printf("Failed to unmarshal player props for event %s: %v", event.Id, err)
+			logging.FromContext(ctx).WarnContext(ctx, "failed to unmarshal player props", "event", event.Id, "error", err)
 			continue
 		}
 
@@ -1,6 +1,7 @@
 package mlb
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -8,10 +9,13 @@ import (
 )
 
 func TestGetAllNBAEventProps(t *testing.T) {
-	props, err := GetPlayerProps()
+	props, err := GetPlayerProps(context.Background(), newTestClient(t))
 	if err != nil {
 		t.Fatalf("Failed to get player props: %v", err) // Fail the test if there's an error
 	}
+	if len(props) != 1 {
+		t.Errorf("Expected props for the one fake event, got %d", len(props))
+	}
 
 	// Iterate over each matchup and print details line by line
 	for i, prop := range props {
@@ -40,9 +44,8 @@ func TestGetAllNBAEventProps(t *testing.T) {
 }
 
 func TestGetAllMLBEvents(t *testing.T) {
-	// Replace with your actual API key or use a mock/stub API key
 	// Call GetAllNBAEvents
-	response, err := GetAllMLBEvents()
+	response, err := GetAllMLBEvents(context.Background(), newTestClient(t))
 	if err != nil {
 		t.Fatalf("Failed to fetch NBA events: %v", err)
 	}
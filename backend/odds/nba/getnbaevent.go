@@ -1,10 +1,13 @@
 package nba
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"sports_api/globals/odds"
+	"sports_api/logging"
+	"sports_api/tracing"
 	"time"
 )
 
@@ -17,16 +20,16 @@ type OddsEvent struct {
 	SportTitle   string    `json:"sport_title"`
 }
 
-func GetAllNBAEvents() (*odds.OddsApiResponse, error) {
+func GetAllNBAEvents(ctx context.Context, oddsClient *odds.Client) (*odds.OddsApiResponse, error) {
 	// Construct the URL for fetching events
 
-	eventsURL := odds.GlobalOddsClient.GetSportEventsURL("basketball_nba")
+	eventsURL := oddsClient.GetSportEventsURL("basketball_nba")
 
 	// No query parameters needed for this endpoint
 	params := map[string]string{}
 
 	// Make API request
-	response, err := odds.GlobalOddsClient.GetOddsRequest(eventsURL, params, nil)
+	response, err := oddsClient.GetOddsRequest(ctx, eventsURL, params, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch events for sport %s: %w", "nba", err)
 	}
@@ -35,9 +38,9 @@ func GetAllNBAEvents() (*odds.OddsApiResponse, error) {
 }
 
 // GetParsedNBAEvents calls GetAllNBAEvents and unmarshals the data into a slice of NBAOddsEvent
-func GetandUnmarshallAllNBAEvents() ([]OddsEvent, error) {
+func GetandUnmarshallAllNBAEvents(ctx context.Context, oddsClient *odds.Client) ([]OddsEvent, error) {
 	// Fetch NBA events
-	response, err := GetAllNBAEvents()
+	response, err := GetAllNBAEvents(ctx, oddsClient)
 	if err != nil {
 		return nil, err
 	}
@@ -58,9 +61,12 @@ func GetandUnmarshallAllNBAEvents() ([]OddsEvent, error) {
 }
 
 // GetPlayerProps fetches player props for all NBA events
-func GetPlayerProps() (MatchupOddsSlice, error) {
+func GetPlayerProps(ctx context.Context, oddsClient *odds.Client) (_ MatchupOddsSlice, err error) {
+	ctx, span := tracing.Start(ctx, "odds/nba.GetPlayerProps")
+	defer tracing.End(span, &err)
+
 	// Get all NBA events
-	events, err := GetandUnmarshallAllNBAEvents()
+	events, err := GetandUnmarshallAllNBAEvents(ctx, oddsClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get NBA events: %w", err)
 	}
@@ -69,30 +75,34 @@ func GetPlayerProps() (MatchupOddsSlice, error) {
 
 	// Loop through each event and fetch player props
 	for _, event := range events {
+		// Stop fetching once the caller has gone away
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
 		// Construct URL for player props using the event ID
-		playerPropsURL := odds.GlobalOddsClient.GetEventOddsURL("basketball_nba", event.Id)
+		playerPropsURL := oddsClient.GetEventOddsURL("basketball_nba", event.Id)
 
-		// No query parameters needed
+		// Markets are listed most important first; trailing ones are dropped when the budget runs low
 		params := map[string]string{"regions": "us",
-			"markets":    "player_points",
-			"oddsFormat": "american"}
-
-		params = map[string]string{"regions": "us",
 			"markets":    "player_points,player_rebounds,player_assists,player_steals,player_points_assists,player_points_rebounds_assists,player_points_rebounds,player_turnovers,player_blocks,player_threes",
 			"oddsFormat": "american"}
 
 		// Make API request for player props
-		response, err := odds.GlobalOddsClient.GetOddsRequest(playerPropsURL, params, nil)
+		response, err := oddsClient.GetOptionalOddsRequest(ctx, playerPropsURL, params, nil)
+		if errors.Is(err, odds.ErrBudgetExceeded) {
+			logging.FromContext(ctx).WarnContext(ctx, "skipping player props for the remaining events", "error", err)
+			break
+		}
 		if err != nil {
-			log.Printf("Failed to fetch player props for event %s: %v", event.Id, err)
+			logging.FromContext(ctx).WarnContext(ctx, "failed to fetch player props", "event", event.Id, "error", err)
 			continue // Skip this event and continue to the next one
 		}
 
 		var m MatchupOdds
 		responseBytes, _ := json.Marshal(response.Data)
 		if err := json.Unmarshal(responseBytes, &m); err != nil {
-			log.Printf("Failed to unmarshal player props for event %s: %v", event.Id, err)
+			logging.FromContext(ctx).WarnContext(ctx, "failed to unmarshal player props", "event", event.Id, "error", err)
 			continue
 		}
 
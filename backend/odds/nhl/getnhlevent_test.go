@@ -1,6 +1,7 @@
 package nhl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -9,10 +10,13 @@ import (
 )
 
 func TestGetAllNHLEventProps(t *testing.T) {
-	props, err := GetPlayerProps()
+	props, err := GetPlayerProps(context.Background(), newTestClient(t))
 	if err != nil {
 		t.Fatalf("Failed to get player props: %v", err) // Fail the test if there's an error
 	}
+	if len(props) != 1 {
+		t.Errorf("Expected props for the one fake event, got %d", len(props))
+	}
 
 	// Iterate over each matchup and print details line by line
 	for i, prop := range props {
@@ -42,9 +46,8 @@ func TestGetAllNHLEventProps(t *testing.T) {
 }
 
 func TestGetAllMLBEvents(t *testing.T) {
-	// Replace with your actual API key or use a mock/stub API key
 	// Call GetAllNBAEvents
-	response, err := GetAllNHLEvents()
+	response, err := GetAllNHLEvents(context.Background(), newTestClient(t))
 	if err != nil {
 		t.Fatalf("Failed to fetch NBA events: %v", err)
 	}
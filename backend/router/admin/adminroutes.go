@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"sports_api/apierror"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/globals/providers"
+	"sports_api/globals/upstream"
+	"strings"
+)
+
+// SetupAdminRoutes registers the cache administration routes behind a bearer
+// token check. Nothing is registered when no token is configured. Failures are
+// recorded with c.Error for the router's ErrorHandler to answer.
+func SetupAdminRoutes(router *gin.Engine, cfg config.Admin, cache db.Cache, clients *providers.Clients) {
+	if cfg.Token == "" {
+		slog.Info("admin routes disabled: no admin token configured")
+		return
+	}
+	upstreams := clients.Upstreams()
+	sets := warmSets(clients)
+
+	adminGroup := router.Group("/admin", requireToken(cfg.Token))
+	{
+		// Lists cached responses, optionally for one upstream and endpoint
+		adminGroup.GET("/cache", func(c *gin.Context) {
+			selected, ok := selectUpstreams(c, upstreams)
+			if !ok {
+				return
+			}
+			entries := []upstream.CachedEntry{}
+			for _, client := range selected {
+				found, err := client.CachedEntries(c.Request.Context(), c.Query("endpoint"))
+				if err != nil {
+					c.Error(apierror.Wrap(apierror.Internal, err))
+					c.Abort()
+					return
+				}
+				entries = append(entries, found...)
+			}
+			c.JSON(http.StatusOK, entries)
+		})
+
+		// Invalidates by URL prefix, or by endpoint name for one or every upstream
+		adminGroup.DELETE("/cache", func(c *gin.Context) {
+			if prefix := c.Query("prefix"); prefix != "" {
+				deleted, err := upstream.InvalidatePrefix(c.Request.Context(), cache, prefix)
+				if err != nil {
+					c.Error(apierror.New(apierror.Internal, "invalidating prefix %s after deleting %d entries: %w", prefix, deleted, err))
+					c.Abort()
+					return
+				}
+				c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+				return
+			}
+
+			endpoint := c.Query("endpoint")
+			if endpoint == "" {
+				c.Error(apierror.New(apierror.Validation, "prefix or endpoint is required"))
+				c.Abort()
+				return
+			}
+			selected, ok := selectUpstreams(c, upstreams)
+			if !ok {
+				return
+			}
+			deleted := 0
+			for _, client := range selected {
+				n, err := client.InvalidateEndpoint(c.Request.Context(), endpoint)
+				deleted += n
+				if err != nil {
+					c.Error(apierror.New(apierror.Internal, "invalidating endpoint %s after deleting %d entries: %w", endpoint, deleted, err))
+					c.Abort()
+					return
+				}
+			}
+			c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+		})
+
+		adminGroup.GET("/cache/warm", func(c *gin.Context) {
+			c.JSON(http.StatusOK, warmSetStatuses(sets))
+		})
+
+		// Starts warming a named set in the background
+		adminGroup.POST("/cache/warm/:set", func(c *gin.Context) {
+			name := c.Param("set")
+			if err := startWarm(c.Request.Context(), sets, name); err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusAccepted, gin.H{"set": name, "status": "started"})
+		})
+	}
+}
+
+// requireToken rejects requests without an "Authorization: Bearer <token>" header matching token.
+func requireToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.Error(apierror.New(apierror.Unauthorized, "admin token required"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// selectUpstreams returns the clients of the upstream named by the upstream
+// query parameter, or every client when it is empty. Unknown names are
+// recorded as a Validation error and abort the request.
+func selectUpstreams(c *gin.Context, upstreams []providers.Upstream) ([]*upstream.Client, bool) {
+	name := c.Query("upstream")
+	var clients []*upstream.Client
+	for _, u := range upstreams {
+		if name == "" || u.Name == name {
+			clients = append(clients, u.Clients...)
+		}
+	}
+	if len(clients) == 0 {
+		c.Error(apierror.New(apierror.Validation, "unknown upstream %s, want nba, mlb, nhl or odds", name))
+		c.Abort()
+		return nil, false
+	}
+	return clients, true
+}
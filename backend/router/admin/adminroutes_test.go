@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/apierror"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/globals/providers"
+	"testing"
+)
+
+// newAdminRouter registers the admin routes behind an error handler that
+// renders apierror envelopes, as the main router's ErrorHandler does.
+func newAdminRouter(cfg config.Admin) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		if last := c.Errors.Last(); last != nil && !c.Writer.Written() {
+			c.JSON(apierror.Response(last.Err))
+		}
+	})
+	SetupAdminRoutes(r, cfg, db.NewMemoryCache(0), providers.New(config.Default(), nil))
+	return r
+}
+
+func TestAdminRoutesRequireToken(t *testing.T) {
+	r := newAdminRouter(config.Admin{Token: "secret"})
+
+	tests := []struct {
+		auth string
+		want int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusOK},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/admin/cache/warm", nil)
+		if test.auth != "" {
+			req.Header.Set("Authorization", test.auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != test.want {
+			t.Errorf("Authorization %q: status = %d, want %d", test.auth, w.Code, test.want)
+		}
+	}
+}
+
+func TestAdminRoutesDisabledWithoutToken(t *testing.T) {
+	r := newAdminRouter(config.Admin{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/cache", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 when no token is configured", w.Code)
+	}
+}
+
+func TestAdminRoutesAnswerErrorsWithEnvelope(t *testing.T) {
+	r := newAdminRouter(config.Admin{Token: "secret"})
+
+	tests := []struct {
+		method string
+		path   string
+		auth   string
+		status int
+		code   string
+	}{
+		{http.MethodGet, "/admin/cache", "", http.StatusUnauthorized, "unauthorized"},
+		{http.MethodGet, "/admin/cache?upstream=nfl", "Bearer secret", http.StatusBadRequest, "invalid_request"},
+		{http.MethodDelete, "/admin/cache", "Bearer secret", http.StatusBadRequest, "invalid_request"},
+		{http.MethodPost, "/admin/cache/warm/nfl-teams", "Bearer secret", http.StatusNotFound, "not_found"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		if test.auth != "" {
+			req.Header.Set("Authorization", test.auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var envelope apierror.Envelope
+		if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("%s %s: body %q is not an envelope: %v", test.method, test.path, w.Body.String(), err)
+		}
+		if w.Code != test.status || envelope.Error.Code != test.code {
+			t.Errorf("%s %s = %d %s, want %d %s", test.method, test.path, w.Code, envelope.Error.Code, test.status, test.code)
+		}
+	}
+}
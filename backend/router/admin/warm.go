@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sports_api/apierror"
+	"sports_api/background"
+	"sports_api/globals/providers"
+	"sports_api/logging"
+	oddsmlb "sports_api/odds/mlb"
+	oddsnba "sports_api/odds/nba"
+	oddsnhl "sports_api/odds/nhl"
+	"sports_api/stats/endpoints/mlb"
+	endpoints "sports_api/stats/endpoints/nba"
+	"sports_api/stats/endpoints/nhl"
+	static "sports_api/stats/static/nba"
+	"sync"
+	"time"
+)
+
+// warmTimeout bounds a single warm-up run.
+const warmTimeout = 10 * time.Minute
+
+var (
+	errUnknownWarmSet = apierror.New(apierror.NotFound, "unknown warm set")
+	errShuttingDown   = apierror.New(apierror.Unavailable, "server is shutting down")
+)
+
+// warmSet is a group of upstream fetches a warm-up runs together.
+type warmSet func(ctx context.Context) error
+
+// warmSets returns the warm sets by name. Each fetch goes through clients, so
+// its responses land in the cache.
+func warmSets(clients *providers.Clients) map[string]warmSet {
+	return map[string]warmSet{
+		"nba-players": func(ctx context.Context) error {
+			if _, err := endpoints.CommonAllPlayers(ctx, clients.NBA, 1, "00", clients.NBA.Season); err != nil {
+				return err
+			}
+			logs, err := endpoints.GetAllNBAPlayerStatsFullSeason(ctx, clients.NBA)
+			if err == nil && len(logs) == 0 {
+				err = errors.New("no player game logs returned")
+			}
+			return err
+		},
+		"wnba-players": func(ctx context.Context) error {
+			_, err := endpoints.CommonAllPlayers(ctx, clients.NBA, 1, "10", clients.NBA.WNBASeason)
+			return err
+		},
+		"nba-matchups": func(ctx context.Context) error {
+			_, err := static.GetNBAMatchupsWithOdds(ctx, clients.NBA, clients.Odds)
+			return err
+		},
+		"nhl-teams": func(ctx context.Context) error {
+			_, err := nhl.GetNHlTeams(ctx, clients.NHL)
+			return err
+		},
+		"mlb-teams": func(ctx context.Context) error {
+			_, err := mlb.GetAllMLBTeams(ctx, clients.MLB)
+			return err
+		},
+		"odds-events": func(ctx context.Context) error {
+			_, nbaErr := oddsnba.GetAllNBAEvents(ctx, clients.Odds)
+			_, mlbErr := oddsmlb.GetAllMLBEvents(ctx, clients.Odds)
+			_, nhlErr := oddsnhl.GetAllNHLEvents(ctx, clients.Odds)
+			return errors.Join(nbaErr, mlbErr, nhlErr)
+		},
+	}
+}
+
+// WarmStatus reports the latest run of a warm set.
+type WarmStatus struct {
+	Set        string    `json:"set"`
+	Running    bool      `json:"running"`
+	StartedAt  time.Time `json:"startedAt,omitzero"`
+	FinishedAt time.Time `json:"finishedAt,omitzero"`
+	Error      string    `json:"error,omitempty"`
+}
+
+var (
+	warmMu     sync.Mutex
+	warmStatus = make(map[string]*WarmStatus)
+)
+
+// startWarm runs the warm set called name from sets in the background. A set
+// runs at most once at a time, and none start once the server is shutting down.
+func startWarm(ctx context.Context, sets map[string]warmSet, name string) error {
+	warm, ok := sets[name]
+	if !ok {
+		return errUnknownWarmSet
+	}
+
+	warmMu.Lock()
+	defer warmMu.Unlock()
+	if status := warmStatus[name]; status != nil && status.Running {
+		return apierror.New(apierror.Conflict, "warm set %s is already running", name)
+	}
+	status := &WarmStatus{Set: name, Running: true, StartedAt: time.Now()}
+
+	// The run outlives the admin request that started it
+	started := background.Go(ctx, func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, warmTimeout)
+		defer cancel()
+		logger := logging.FromContext(ctx).With("set", name)
+		logger.InfoContext(ctx, "warming cache set")
+		err := warm(ctx)
+
+		warmMu.Lock()
+		defer warmMu.Unlock()
+		status.Running = false
+		status.FinishedAt = time.Now()
+		if err != nil {
+			status.Error = err.Error()
+			logger.WarnContext(ctx, "warming cache set failed", "error", err)
+			return
+		}
+		logger.InfoContext(ctx, "warmed cache set", "duration", status.FinishedAt.Sub(status.StartedAt))
+	})
+	if !started {
+		return errShuttingDown
+	}
+	warmStatus[name] = status
+	return nil
+}
+
+// warmSetStatuses lists every warm set with its latest run, sorted by name.
+func warmSetStatuses(sets map[string]warmSet) []WarmStatus {
+	warmMu.Lock()
+	defer warmMu.Unlock()
+
+	statuses := make([]WarmStatus, 0, len(sets))
+	for name := range sets {
+		if status := warmStatus[name]; status != nil {
+			statuses = append(statuses, *status)
+		} else {
+			statuses = append(statuses, WarmStatus{Set: name})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Set < statuses[j].Set })
+	return statuses
+}
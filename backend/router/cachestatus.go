@@ -0,0 +1,50 @@
+package router
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"sports_api/globals/upstream"
+)
+
+// CacheHeader is set to "stale" on responses built from at least one cached
+// upstream response served past its TTL, e.g. while the upstream is down.
+const CacheHeader = "X-Cache"
+
+// CacheStatus sets CacheHeader on responses whose upstream calls were served
+// stale. The header is added as the response is written, since handlers make
+// their upstream calls before writing anything.
+func CacheStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := upstream.WithStaleReport(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &cacheStatusWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Next()
+	}
+}
+
+// cacheStatusWriter sets CacheHeader just before the response is written.
+type cacheStatusWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+func (w *cacheStatusWriter) setHeader() {
+	if !w.Written() && upstream.ServedStale(w.ctx) {
+		w.Header().Set(CacheHeader, string(upstream.CacheStale))
+	}
+}
+
+func (w *cacheStatusWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheStatusWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheStatusWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/db"
+	"sports_api/globals/upstream"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheStatusMarksStaleResponses(t *testing.T) {
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"players": []}`))
+	}))
+	defer server.Close()
+
+	client := upstream.NewClient("Test", server.URL+"/", db.NewMemoryCache(0), nil)
+	client.CacheTTL = time.Nanosecond
+	client.RetryPolicy = upstream.NoRetry
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CacheStatus(), ErrorHandler())
+	r.GET("/players", func(c *gin.Context) {
+		resp, err := client.Get(c.Request.Context(), "players", nil, "", nil)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, resp.Data)
+	})
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/players", nil))
+		return w
+	}
+	if w := get(); w.Code != http.StatusOK || w.Header().Get(CacheHeader) != "" {
+		t.Errorf("fresh response: status %d, %s %q, want 200 without the header", w.Code, CacheHeader, w.Header().Get(CacheHeader))
+	}
+	down.Store(true)
+	if w := get(); w.Code != http.StatusOK || w.Header().Get(CacheHeader) != "stale" {
+		t.Errorf("response while upstream is down: status %d, %s %q, want 200 and stale", w.Code, CacheHeader, w.Header().Get(CacheHeader))
+	}
+}
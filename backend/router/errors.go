@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"sports_api/apierror"
+	"sports_api/logging"
+)
+
+// ErrorHandler answers requests whose handler recorded an error with c.Error
+// instead of writing a response. The last error picks the status, and the
+// body is an apierror.Envelope.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+		status, body := apierror.Response(last.Err)
+		if status >= 500 {
+			logging.FromContext(c.Request.Context()).Error("request failed", "path", c.Request.URL.Path, "error", last.Err)
+		}
+		c.JSON(status, body)
+	}
+}
+
+// noRoute answers unknown paths with a not_found envelope.
+func noRoute(c *gin.Context) {
+	c.Error(apierror.New(apierror.NotFound, "no route for %s %s", c.Request.Method, c.Request.URL.Path))
+}
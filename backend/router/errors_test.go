@@ -0,0 +1,67 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/apierror"
+	"sports_api/globals/odds"
+	"sports_api/globals/upstream"
+	"testing"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.NoRoute(noRoute)
+	fail := func(err error) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Error(err) }
+	}
+	r.GET("/validation", fail(apierror.New(apierror.Validation, "Invalid playerID, must be an integer")))
+	r.GET("/upstream-404", fail(&upstream.StatusError{StatusCode: http.StatusNotFound}))
+	r.GET("/circuit", fail(upstream.ErrCircuitOpen))
+	r.GET("/quota", fail(odds.ErrBudgetExceeded))
+	r.GET("/internal", fail(errors.New("nil map")))
+	r.GET("/canceled", fail(fmt.Errorf("fetching players: %w", context.Canceled)))
+	r.GET("/written", func(c *gin.Context) {
+		c.Error(errors.New("logged only"))
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/validation", http.StatusBadRequest, "invalid_request"},
+		{"/upstream-404", http.StatusNotFound, "not_found"},
+		{"/circuit", http.StatusServiceUnavailable, "upstream_unavailable"},
+		{"/quota", http.StatusServiceUnavailable, "quota_exhausted"},
+		{"/internal", http.StatusInternalServerError, "internal_error"},
+		{"/canceled", apierror.StatusClientClosedRequest, "request_canceled"},
+		{"/missing", http.StatusNotFound, "not_found"},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		var envelope apierror.Envelope
+		if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("%s body %q is not an error envelope: %v", test.path, w.Body, err)
+		}
+		if w.Code != test.status || envelope.Error.Code != test.code || envelope.Error.Message == "" {
+			t.Errorf("%s = %d %+v, want %d %s", test.path, w.Code, envelope.Error, test.status, test.code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/written", nil))
+	if w.Code != http.StatusOK || w.Body.String() != `{"ok":true}` {
+		t.Errorf("/written = %d %s, want the handler's own response", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	"sports_api/db"
+	"sports_api/globals/odds"
+	"sports_api/globals/providers"
+	"sports_api/globals/upstream"
+	"time"
+)
+
+// Readiness statuses, from best to worst.
+const (
+	statusOK          = "ok"
+	statusDegraded    = "degraded"
+	statusUnavailable = "unavailable"
+)
+
+// readyTimeout bounds the cache check made by /readyz.
+var readyTimeout = 2 * time.Second
+
+// readiness is the body of /readyz.
+type readiness struct {
+	Status    string           `json:"status"`
+	Cache     cacheStatus      `json:"cache"`
+	Providers []providerStatus `json:"providers"`
+}
+
+type cacheStatus struct {
+	Backend string `json:"backend"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+type providerStatus struct {
+	upstream.ProviderHealth
+	Status   string            `json:"status"`
+	Circuits map[string]string `json:"circuits"` // Circuit state by host
+	// Odds only
+	APIKeySet      *bool            `json:"apiKeySet,omitempty"`
+	APIKeys        []odds.KeyStatus `json:"apiKeys,omitempty"`
+	QuotaRemaining *int             `json:"quotaRemaining,omitempty"` // Absent until the API has reported it
+}
+
+// SetupHealthRoutes registers /healthz, which answers as long as the process
+// can serve requests, and /readyz, which fails with 503 while the cache
+// backend is unreachable. Upstream problems only mark readiness degraded,
+// since cached responses can still be served; the body reports each
+// provider's last success and failure, circuit states and the Odds API quota.
+func SetupHealthRoutes(router *gin.Engine, backend string, cache db.Cache, clients *providers.Clients) {
+	router.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": statusOK})
+	})
+
+	router.GET("/readyz", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+		defer cancel()
+
+		report := readiness{Status: statusOK, Cache: cacheStatus{Backend: backend, Status: statusOK}}
+		if err := cache.Ping(ctx); err != nil {
+			report.Cache.Status = statusUnavailable
+			report.Cache.Error = err.Error()
+		}
+
+		circuits := make(map[string]upstream.CircuitState)
+		for _, stats := range upstream.DefaultBreaker.Stats() {
+			circuits[stats.Host] = stats.State
+		}
+		for _, u := range clients.Upstreams() {
+			provider := providerReadiness(u.Clients, circuits, clients.Odds)
+			report.Providers = append(report.Providers, provider)
+			if provider.Status != statusOK {
+				report.Status = statusDegraded
+			}
+		}
+
+		status := http.StatusOK
+		if report.Cache.Status != statusOK {
+			report.Status = statusUnavailable
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	})
+}
+
+// providerReadiness reports on the clients of one provider, using the
+// circuit state of each host they call, and on the API keys of oddsClient
+// when they are its clients.
+func providerReadiness(clients []*upstream.Client, circuits map[string]upstream.CircuitState, oddsClient *odds.Client) providerStatus {
+	name := clients[0].Name
+	health := upstream.ProviderHealth{Provider: name}
+	if tracker := clients[0].Health; tracker != nil {
+		health = tracker.Get(name)
+	}
+	provider := providerStatus{ProviderHealth: health, Status: statusOK, Circuits: make(map[string]string)}
+	if health.Failing() {
+		provider.Status = statusDegraded
+	}
+
+	for _, client := range clients {
+		u, err := url.Parse(client.BaseURL)
+		if err != nil {
+			continue
+		}
+		state := circuits[u.Host] // Hosts never called are closed
+		provider.Circuits[u.Host] = state.String()
+		switch state {
+		case upstream.CircuitOpen:
+			provider.Status = statusUnavailable
+		case upstream.CircuitHalfOpen:
+			if provider.Status == statusOK {
+				provider.Status = statusDegraded
+			}
+		}
+	}
+
+	if clients[0] == oddsClient.Client {
+		keys := oddsClient.Keys
+		keySet := keys.Len() > 0
+		provider.APIKeySet = &keySet
+		provider.APIKeys = keys.Status()
+		if remaining := keys.Remaining(); remaining >= 0 {
+			provider.QuotaRemaining = &remaining
+			if remaining == 0 {
+				provider.Status = statusUnavailable
+			}
+		}
+		if keys.Active() == 0 {
+			provider.Status = statusUnavailable
+		}
+	}
+	return provider
+}
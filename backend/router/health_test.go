@@ -0,0 +1,62 @@
+package router
+
+import (
+	"encoding/json"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/globals/providers"
+	"sports_api/globals/upstream"
+	"testing"
+)
+
+func TestHealthRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	upstream.DefaultHealth.Record("MLB", &upstream.StatusError{StatusCode: http.StatusBadGateway})
+	server := miniredis.RunT(t)
+	r := gin.New()
+	SetupHealthRoutes(r, "redis", db.NewRedisCache(server.Addr()), providers.New(config.Default(), nil))
+
+	get := func(path string) (int, readiness) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var report readiness
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Fatalf("%s body %q is not JSON: %v", path, w.Body.String(), err)
+		}
+		return w.Code, report
+	}
+
+	if status, report := get("/healthz"); status != http.StatusOK || report.Status != statusOK {
+		t.Errorf("/healthz = %d %q, want 200 ok", status, report.Status)
+	}
+
+	status, report := get("/readyz")
+	if status != http.StatusOK || report.Status != statusDegraded || report.Cache.Status != statusOK {
+		t.Errorf("/readyz = %d %+v, want 200 degraded with the cache ok", status, report)
+	}
+	byName := make(map[string]providerStatus)
+	for _, p := range report.Providers {
+		byName[p.Provider] = p
+	}
+	if p := byName["NBA"]; p.Status != statusOK || p.Circuits["stats.nba.com"] != "closed" {
+		t.Errorf("NBA = %+v, want ok with a closed circuit", p)
+	}
+	if p := byName["MLB"]; p.Status != statusDegraded || p.Error == "" {
+		t.Errorf("MLB = %+v, want degraded by its last call failing", p)
+	}
+	if p := byName["NHL"]; len(p.Circuits) != 2 {
+		t.Errorf("NHL circuits = %v, want both the stats and web hosts", p.Circuits)
+	}
+	if p := byName["Odds"]; p.Status != statusUnavailable || p.APIKeySet == nil || *p.APIKeySet {
+		t.Errorf("Odds = %+v, want unavailable without an API key", p)
+	}
+
+	server.Close()
+	if status, report := get("/readyz"); status != http.StatusServiceUnavailable || report.Cache.Status != statusUnavailable {
+		t.Errorf("/readyz with Redis down = %d %+v, want 503", status, report.Cache)
+	}
+}
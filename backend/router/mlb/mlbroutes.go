@@ -3,16 +3,21 @@ package mlb
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"sports_api/globals/providers"
 	"sports_api/stats/endpoints/mlb"
 )
 
 // SetupNBARoutes registers NBA-related routes in the Gin engine
-func SetupMLBRoutes(router *gin.Engine) {
+func SetupMLBRoutes(router *gin.Engine, clients *providers.Clients) {
 	nbaGroup := router.Group("/mlb")
 	{
 		nbaGroup.GET("/teams", func(c *gin.Context) {
-
-			c.JSON(http.StatusOK, mlb.GetAndParseMLBTeams())
+			teams, err := mlb.GetAndParseMLBTeams(c.Request.Context(), clients.MLB)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, teams)
 		})
 	}
 }
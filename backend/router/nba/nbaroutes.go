@@ -3,24 +3,40 @@ package nba
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"sports_api/apierror"
+	"sports_api/globals/providers"
 	endpoints "sports_api/stats/endpoints/nba"
 	static "sports_api/stats/static/nba"
 	"strconv"
 )
 
 // SetupNBARoutes registers NBA-related routes in the Gin engine
-func SetupNBARoutes(router *gin.Engine) {
+func SetupNBARoutes(router *gin.Engine, clients *providers.Clients) {
 	nbaGroup := router.Group("/nba")
 	{
 		nbaGroup.GET("/teams", func(c *gin.Context) {
-
-			c.JSON(http.StatusOK, static.GetNBATeamsWithPlayers())
+			teams, err := static.GetNBATeamsWithPlayers(c.Request.Context(), clients.NBA)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, teams)
 		})
 		nbaGroup.GET("v1/matchups", func(c *gin.Context) {
-			c.JSON(http.StatusOK, static.GetNBAMatchups())
+			matchups, err := static.GetNBAMatchups(c.Request.Context(), clients.NBA)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, matchups)
 		})
 		nbaGroup.GET("v2/matchups", func(c *gin.Context) {
-			c.JSON(http.StatusOK, static.GetNBAMatchupsWithOdds())
+			matchups, err := static.GetNBAMatchupsWithOdds(c.Request.Context(), clients.NBA, clients.Odds)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, matchups)
 		})
 		nbaGroup.GET("v2/player/gamelogs", func(c *gin.Context) {
 			playerIDStr := c.Query("playerID")
@@ -32,14 +48,19 @@ func SetupNBARoutes(router *gin.Engine) {
 			}
 			playerID, err := strconv.Atoi(playerIDStr)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playerID, must be an integer"})
+				c.Error(apierror.New(apierror.Validation, "Invalid playerID, must be an integer"))
 				return
 			}
 
-			gameLogs := endpoints.GetCurrentSeasonStats(period).GetPlayerGameLog(playerID)
+			stats, err := endpoints.GetCurrentSeasonStats(c.Request.Context(), clients.NBA, period)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			gameLogs := stats.GetPlayerGameLog(playerID)
 
-			if gameLogs == nil || len(gameLogs) == 0 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "No Game Logs found for the given period"})
+			if len(gameLogs) == 0 {
+				c.Error(apierror.New(apierror.NotFound, "No Game Logs found for player %d in period %s", playerID, period))
 				return
 			}
 
@@ -47,15 +68,19 @@ func SetupNBARoutes(router *gin.Engine) {
 		})
 
 		nbaGroup.GET("/matchups/players", func(c *gin.Context) {
-
-			c.JSON(http.StatusOK, static.GetActivePlayerForToday())
+			players, err := static.GetActivePlayerForToday(c.Request.Context(), clients.NBA, clients.Odds)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, players)
 		})
 
 		nbaGroup.GET("/players/current", func(c *gin.Context) {
-			players := endpoints.GetAllNBAPlayers()
-			if players == nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "no players"})
-
+			players, err := endpoints.GetAllNBAPlayers(c.Request.Context(), clients.NBA)
+			if err != nil {
+				c.Error(err)
+				return
 			}
 			c.JSON(http.StatusOK, players)
 		})
@@ -68,10 +93,14 @@ func SetupNBARoutes(router *gin.Engine) {
 
 			league := "00"
 
-			gamelogs := endpoints.GetPlayerGameLog(playerID, season, seasonType, &league)
+			gamelogs, err := endpoints.GetPlayerGameLog(c.Request.Context(), clients.NBA, playerID, season, seasonType, &league)
+			if err != nil {
+				c.Error(err)
+				return
+			}
 
-			if gamelogs == nil || len(gamelogs) == 0 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "No Game Logs"})
+			if len(gamelogs) == 0 {
+				c.Error(apierror.New(apierror.NotFound, "No Game Logs found for player %s", playerID))
 				return
 			}
 
@@ -86,21 +115,26 @@ func SetupNBARoutes(router *gin.Engine) {
 
 			playerID, err := strconv.Atoi(playerIDStr)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playerID, must be an integer"})
+				c.Error(apierror.New(apierror.Validation, "Invalid playerID, must be an integer"))
 				return
 			}
 			numGames, err := strconv.Atoi(numGamesStr)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid numGames, must be an integer"})
+				c.Error(apierror.New(apierror.Validation, "Invalid numGames, must be an integer"))
 				return
 			}
 			threshold, err := strconv.ParseFloat(thresholdStr, 64)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid threshold, must be an integer"})
+				c.Error(apierror.New(apierror.Validation, "Invalid threshold, must be a number"))
 				return
 			}
 
-			gameLogs := endpoints.GetCurrentSeasonStats("full").GetPlayerGameLog(playerID)
+			stats, err := endpoints.GetCurrentSeasonStats(c.Request.Context(), clients.NBA, "Season")
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			gameLogs := stats.GetPlayerGameLog(playerID)
 			hitRate := CalculateHitRate(gameLogs, stat, numGames, threshold)
 
 			c.JSON(http.StatusOK, gin.H{
@@ -119,16 +153,21 @@ func SetupNBARoutes(router *gin.Engine) {
 
 			playerID, err := strconv.Atoi(playerIDStr)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playerID, must be an integer"})
+				c.Error(apierror.New(apierror.Validation, "Invalid playerID, must be an integer"))
 				return
 			}
 			threshold, err := strconv.ParseFloat(thresholdStr, 64)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid threshold, must be an integer"})
+				c.Error(apierror.New(apierror.Validation, "Invalid threshold, must be a number"))
 				return
 			}
 
-			gameLogs := endpoints.GetCurrentSeasonStats("full").GetPlayerGameLog(playerID)
+			stats, err := endpoints.GetCurrentSeasonStats(c.Request.Context(), clients.NBA, "Season")
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			gameLogs := stats.GetPlayerGameLog(playerID)
 			streak := CalculateStreak(gameLogs, stat, threshold)
 
 			c.JSON(http.StatusOK, gin.H{
@@ -3,24 +3,29 @@ package nba
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"sports_api/globals/providers"
 	"sports_api/stats/endpoints/nba"
 	static "sports_api/stats/static/nba"
 )
 
 // SetupNBARoutes registers NBA-related routes in the Gin engine
-func SetupWNBARoutes(router *gin.Engine) {
+func SetupWNBARoutes(router *gin.Engine, clients *providers.Clients) {
 	wnbaGroup := router.Group("/wnba")
 	{
 		wnbaGroup.GET("/teams", func(c *gin.Context) {
-
-			c.JSON(http.StatusOK, static.GetWNBATeamsWithPlayers())
+			teams, err := static.GetWNBATeamsWithPlayers(c.Request.Context(), clients.NBA)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, teams)
 		})
 
 		wnbaGroup.GET("/players/current", func(c *gin.Context) {
-			players := nba.GetAllWNBAPlayers()
-			if players == nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "no players"})
-
+			players, err := nba.GetAllWNBAPlayers(c.Request.Context(), clients.NBA)
+			if err != nil {
+				c.Error(err)
+				return
 			}
 			c.JSON(http.StatusOK, players)
 		})
@@ -34,13 +39,14 @@ func SetupWNBARoutes(router *gin.Engine) {
 			leagueID := "10"
 
 			// Call PlayerGameLog function
-			result, err := nba.PlayerGameLog(playerID, season, seasonType, &leagueID)
+			result, err := nba.PlayerGameLog(c.Request.Context(), clients.NBA, playerID, season, seasonType, &leagueID)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				c.Error(err)
 				return
 			}
 			dict, err := result.GetNormalizedDict2()
 			if err != nil {
+				c.Error(err)
 				return
 			}
 
@@ -3,25 +3,28 @@ package nhl
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"sports_api/globals/providers"
 	"sports_api/stats/endpoints/nhl"
 	static "sports_api/stats/static/nhl"
 )
 
 // SetupNBARoutes registers NBA-related routes in the Gin engine
-func SetupNHLRoutes(router *gin.Engine) {
+func SetupNHLRoutes(router *gin.Engine, clients *providers.Clients) {
 	nbaGroup := router.Group("/nhl")
 	{
 		nbaGroup.GET("/teams", func(c *gin.Context) {
-			teams, err := nhl.GetAndParseNHLTeams()
-			if err != nil || len(teams) == 0 {
-				c.JSON(http.StatusInternalServerError, gin.H{"error getting nhl teams": err})
+			teams, err := nhl.GetAndParseNHLTeams(c.Request.Context(), clients.NHL)
+			if err != nil {
+				c.Error(err)
+				return
 			}
 			c.JSON(http.StatusOK, teams)
 		})
 		nbaGroup.GET("/matchups", func(c *gin.Context) {
-			matchups := static.GetNHLMatchupsWithOdds()
-			if len(matchups) == 0 || matchups == nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error getting nhl teams": "No Matchups found"})
+			matchups, err := static.GetNHLMatchupsWithOdds(c.Request.Context(), clients.NHL, clients.Odds)
+			if err != nil {
+				c.Error(err)
+				return
 			}
 			c.JSON(http.StatusOK, matchups)
 		})
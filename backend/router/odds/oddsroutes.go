@@ -0,0 +1,18 @@
+package odds
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sports_api/globals/providers"
+)
+
+// SetupOddsRoutes registers The Odds API routes in the Gin engine
+func SetupOddsRoutes(router *gin.Engine, clients *providers.Clients) {
+	oddsGroup := router.Group("/odds")
+	{
+		// Current request quota, budget and credits spent per sport and market
+		oddsGroup.GET("/quota", func(c *gin.Context) {
+			c.JSON(http.StatusOK, clients.Odds.Quota.Usage())
+		})
+	}
+}
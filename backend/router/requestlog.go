@@ -0,0 +1,52 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"regexp"
+	"sports_api/logging"
+	"time"
+)
+
+// RequestIDHeader carries the request ID. An ID sent by the caller is kept
+// so logs can be correlated across services; otherwise one is generated.
+const RequestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestLogger assigns every request an ID, echoes it in the response,
+// makes a logger tagged with it available through the request's context and
+// logs the request once it completes. The query string is left out of the
+// log because it may hold credentials.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = logging.NewRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		ctx := logging.WithRequestID(logging.NewContext(c.Request.Context(), logger), id)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		logging.FromContext(ctx).Log(ctx, level, "request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"duration", time.Since(start),
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
@@ -0,0 +1,54 @@
+package router
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/logging"
+	"strings"
+	"testing"
+)
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(RequestLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	var seen string
+	r.GET("/teams", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		logging.FromContext(c.Request.Context()).Info("handler ran")
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		sent string
+		keep bool
+	}{
+		{"", false},
+		{"client-id.42", true},
+		{"bad id\nwith newline", false},
+	}
+	for _, test := range tests {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/teams?apiKey=secret", nil)
+		if test.sent != "" {
+			req.Header.Set(RequestIDHeader, test.sent)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		id := w.Header().Get(RequestIDHeader)
+		if id == "" || id != seen || (id == test.sent) != test.keep {
+			t.Errorf("sent %q: response ID %q, handler saw %q", test.sent, id, seen)
+		}
+		if got := strings.Count(buf.String(), "request_id="+id); got != 2 {
+			t.Errorf("sent %q: %d log lines tagged with %s, want 2:\n%s", test.sent, got, id, buf.String())
+		}
+		if strings.Contains(buf.String(), "secret") {
+			t.Errorf("request log leaks the query string:\n%s", buf.String())
+		}
+	}
+}
@@ -3,30 +3,46 @@ package router
 import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"log/slog"
+	"sports_api/config"
+	"sports_api/db"
+	"sports_api/globals/providers"
+	"sports_api/metrics"
+	"sports_api/router/admin"
 	"sports_api/router/mlb"
 	"sports_api/router/nba"
 	"sports_api/router/nhl"
+	"sports_api/router/odds"
 	"time"
 )
 
-// SetupRouter initializes the main router and includes league-specific routes with CORS enabled
-func SetupRouter() *gin.Engine {
-	r := gin.Default()
+// SetupRouter initializes the main router and includes league-specific routes with CORS enabled.
+// Every route fetches through clients.
+func SetupRouter(cfg *config.Config, cache db.Cache, clients *providers.Clients) *gin.Engine {
+	r := gin.New()
+	r.Use(RequestLogger(slog.Default()), Tracing(), RequestMetrics(), gin.Recovery())
 
 	// Configure CORS settings
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"}, // Adjust to specific domains if needed
+		AllowOrigins:     cfg.Server.CORSOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", RequestIDHeader, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", RequestIDHeader, CacheHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour, // Cache the preflight request for 12 hours
 	}))
+	r.Use(CacheStatus(), ErrorHandler())
+	r.NoRoute(noRoute)
+
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	SetupHealthRoutes(r, cfg.Cache.Backend, cache, clients)
 
 	// Setup routes
-	nba.SetupNBARoutes(r)
-	nba.SetupWNBARoutes(r)
-	mlb.SetupMLBRoutes(r)
-	nhl.SetupNHLRoutes(r)
+	nba.SetupNBARoutes(r, clients)
+	nba.SetupWNBARoutes(r, clients)
+	mlb.SetupMLBRoutes(r, clients)
+	nhl.SetupNHLRoutes(r, clients)
+	odds.SetupOddsRoutes(r, clients)
+	admin.SetupAdminRoutes(r, cfg.Admin, cache, clients)
 	return r
 }
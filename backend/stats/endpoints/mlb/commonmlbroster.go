@@ -1,7 +1,9 @@
 package mlb
 
 import (
+	"context"
 	"encoding/json"
+	"sports_api/apierror"
 	"sports_api/globals/mlb"
 )
 
@@ -27,10 +29,10 @@ type MLBPlayer struct {
 	Pitching []PitchingStats
 }
 
-func (team *MLBTeam) GetRoster() error {
+func (team *MLBTeam) GetRoster(ctx context.Context, session *mlb.Client) error {
 	params := map[string]string{}
 
-	resp, err := mlb.MLBSession.MLBGetRequest(team.Link+"/roster", params, "", nil)
+	resp, err := session.MLBGetRequest(ctx, team.Link+"/roster", params, "", nil)
 	if err != nil {
 		return err
 	}
@@ -40,7 +42,7 @@ func (team *MLBTeam) GetRoster() error {
 	}
 	err = json.Unmarshal(marshal, &team.Roster)
 	if err != nil {
-		return err
+		return apierror.New(apierror.Decode, "failed to decode %s roster: %w", team.Name, err)
 	}
 
 	return nil
@@ -1,17 +1,19 @@
 package mlb
 
 import (
-	"fmt"
+	"context"
 	"testing"
 )
 
 func TestGetMLBRoster(t *testing.T) {
-
-	resp := GetAndParseMLBTeams()
-
-	if resp == nil {
-		t.Fatal("Expected a response, got nil")
+	resp, err := GetAndParseMLBTeams(context.Background(), newTestSession(t))
+	if err != nil {
+		t.Fatalf("Expected a response, got error: %v", err)
+	}
+	if len(resp[0].Roster) != 2 {
+		t.Fatalf("Expected 2 players, got %d", len(resp[0].Roster))
+	}
+	if name := resp[0].Roster[0].Person.FullName; name != "Hitter 147" {
+		t.Errorf("Expected Hitter 147, got %q", name)
 	}
-	fmt.Println(resp[0].Roster[0].Person)
-
 }
@@ -1,9 +1,11 @@
 package mlb
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"sports_api/apierror"
 	"sports_api/globals/mlb"
+	"sports_api/tracing"
 )
 
 type MLBTeam struct {
@@ -30,34 +32,41 @@ type MLBTeam struct {
 	Roster          []MLBPlayer
 }
 
-func GetAllMLBTeams() (*mlb.MLBResponse, error) {
+func GetAllMLBTeams(ctx context.Context, session *mlb.Client) (*mlb.MLBResponse, error) {
 	params := map[string]string{
 		"sportId": "1",
 	}
 
 	endpoint := "/api/v1/teams"
 
-	return mlb.MLBSession.MLBGetRequest(endpoint, params, "", nil)
+	return session.MLBGetRequest(ctx, endpoint, params, "", nil)
 }
 
-func GetAndParseMLBTeams() []*MLBTeam {
-	resp, err := GetAllMLBTeams()
+func GetAndParseMLBTeams(ctx context.Context, session *mlb.Client) (_ []*MLBTeam, err error) {
+	ctx, span := tracing.Start(ctx, "endpoints/mlb.GetAndParseMLBTeams")
+	defer tracing.End(span, &err)
+
+	resp, err := GetAllMLBTeams(ctx, session)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+	data, ok := resp.Data.(map[string]interface{})
+	if !ok {
+		return nil, apierror.New(apierror.Decode, "unexpected MLB teams response")
 	}
-	i := resp.Data.(map[string]interface{})["teams"]
 	var teams []*MLBTeam
-	marshal, err := json.Marshal(i)
+	marshal, err := json.Marshal(data["teams"])
 	if err != nil {
-		return nil
+		return nil, apierror.Wrap(apierror.Decode, err)
 	}
 	err = json.Unmarshal(marshal, &teams)
 	if err != nil {
-		fmt.Println(err)
-		return nil
+		return nil, apierror.New(apierror.Decode, "failed to decode MLB teams: %w", err)
 	}
 	for _, v := range teams {
-		err = v.GetRoster()
+		if err := v.GetRoster(ctx, session); err != nil {
+			return nil, err
+		}
 	}
-	return teams
+	return teams, nil
 }
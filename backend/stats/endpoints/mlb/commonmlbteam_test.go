@@ -1,16 +1,16 @@
 package mlb
 
 import (
-	"fmt"
+	"context"
 	"testing"
 )
 
 func TestGetMLBTeams(t *testing.T) {
-	resp := GetAndParseMLBTeams()
-	fmt.Println(resp)
-
-	if resp == nil {
-		t.Fatal("Expected a response, got nil")
+	resp, err := GetAndParseMLBTeams(context.Background(), newTestSession(t))
+	if err != nil {
+		t.Fatalf("Expected a response, got error: %v", err)
+	}
+	if len(resp) != 2 || resp[0].Abbreviation != "NYY" || resp[1].Abbreviation != "LAD" {
+		t.Errorf("Expected NYY and LAD, got %+v", resp)
 	}
-
 }
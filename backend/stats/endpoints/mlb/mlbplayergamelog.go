@@ -1,8 +1,11 @@
 package mlb
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"sports_api/apierror"
 	"sports_api/globals/mlb"
 )
 
@@ -175,13 +178,11 @@ func (player *MLBPlayer) SetStats(stats interface{}) {
 	case []PitchingStats:
 		player.Pitching = v
 	default:
-		fmt.Println("Unsupported stats type")
+		slog.Warn("unsupported MLB stats type", "type", fmt.Sprintf("%T", stats))
 	}
 }
 
-func (player *MLBPlayer) GetGameLog(year, gameType string) error {
-	fmt.Println(player.Position.Name)
-
+func (player *MLBPlayer) GetGameLog(ctx context.Context, session *mlb.Client, year, gameType string) error {
 	params := map[string]string{
 		"stats":    "gameLog",
 		"season":   year,
@@ -191,14 +192,14 @@ func (player *MLBPlayer) GetGameLog(year, gameType string) error {
 
 	url := player.Person.Link + "/stats"
 
-	resp, err := mlb.MLBSession.MLBGetRequest(url, params, "", nil)
+	resp, err := session.MLBGetRequest(ctx, url, params, "", nil)
 	if err != nil {
 		return err
 	}
 
 	if len(resp.Data.(map[string]interface{})["stats"].([]interface{})) == 0 {
 
-		return fmt.Errorf("no Result")
+		return apierror.New(apierror.Decode, "no Result")
 	}
 	i := resp.Data.(map[string]interface{})["stats"].([]interface{})[0]
 	displayName := i.(map[string]interface{})["group"].(map[string]interface{})["displayName"].(string)
@@ -227,7 +228,7 @@ func (player *MLBPlayer) GetGameLog(year, gameType string) error {
 		}
 		player.SetStats(hitting)
 	default:
-		return fmt.Errorf("unexpected format")
+		return apierror.New(apierror.Decode, "unexpected format")
 
 	}
 
@@ -1,25 +1,24 @@
 package mlb
 
 import (
-	"fmt"
+	"context"
 	"testing"
 )
 
 func TestSpringTraingGameLog(t *testing.T) {
-
-	resp := GetAndParseMLBTeams()
-	if resp == nil {
-		t.Fatal("Expected a response, got nil")
+	session := newTestSession(t)
+	resp, err := GetAndParseMLBTeams(context.Background(), session)
+	if err != nil {
+		t.Fatalf("Expected a response, got error: %v", err)
 	}
 	for _, team := range resp {
 		for _, player := range team.Roster {
-			err := player.GetGameLog("2024", "R")
-			if err != nil {
-				fmt.Println(err)
+			if err := player.GetGameLog(context.Background(), session, "2024", "R"); err != nil {
+				t.Errorf("%s game log returned error: %v", player.Person.FullName, err)
+			}
+			if len(player.Hitting)+len(player.Pitching) != 1 {
+				t.Errorf("%s has %d hitting and %d pitching games, want 1", player.Person.FullName, len(player.Hitting), len(player.Pitching))
 			}
-			fmt.Println(player.Hitting, player.Pitching)
-
 		}
 	}
-
 }
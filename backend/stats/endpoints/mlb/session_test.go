@@ -0,0 +1,48 @@
+package mlb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/config"
+	"sports_api/globals/mlb"
+	"strings"
+	"testing"
+)
+
+// newTestSession returns an MLB client with the default configuration, pointed
+// at a fake statsapi.mlb.com that lives as long as t. It lists two teams of
+// one hitter and one pitcher each, and every player has one game logged.
+func newTestSession(t *testing.T) *mlb.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case path == "/api/v1/teams":
+			fmt.Fprint(w, `{"teams": [
+				{"id": 147, "name": "New York Yankees", "abbreviation": "NYY", "link": "/api/v1/teams/147"},
+				{"id": 119, "name": "Los Angeles Dodgers", "abbreviation": "LAD", "link": "/api/v1/teams/119"}
+			]}`)
+		case strings.HasPrefix(path, "/api/v1/teams/") && strings.HasSuffix(path, "/roster"):
+			id := strings.TrimSuffix(strings.TrimPrefix(path, "/api/v1/teams/"), "/roster")
+			fmt.Fprintf(w, `{"roster": [
+				{"person": {"id": %[1]s1, "fullName": "Hitter %[1]s", "link": "/api/v1/people/%[1]s1"}, "position": {"abbreviation": "CF", "type": "Outfielder"}},
+				{"person": {"id": %[1]s2, "fullName": "Pitcher %[1]s", "link": "/api/v1/people/%[1]s2"}, "position": {"abbreviation": "P", "type": "Pitcher"}}
+			]}`, id)
+		case strings.HasPrefix(path, "/api/v1/people/") && strings.HasSuffix(path, "/stats"):
+			group := "hitting"
+			if strings.HasSuffix(strings.TrimSuffix(path, "/stats"), "2") {
+				group = "pitching"
+			}
+			fmt.Fprintf(w, `{"stats": [{"group": {"displayName": %q}, "splits": [{"date": "2024-03-28", "season": "2024", "gameType": "R", "stat": {"atBats": 4, "hits": 2}}]}]}`, group)
+		default:
+			t.Errorf("MLB host got %s", path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := config.Default()
+	cfg.MLB.BaseURL = server.URL
+	return mlb.NewMLBClient(cfg, nil)
+}
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
@@ -25,12 +26,12 @@ import (
 //
 // Example Usage:
 //
-//	leaders, err := AllTimeLeadersGrids("00", "Totals", "Regular Season", 10)
+//	leaders, err := AllTimeLeadersGrids(ctx, "00", "Totals", "Regular Season", 10)
 //	if err != nil {
 //	    log.Fatal("Error fetching all-time leaders:", err)
 //	}
 //	fmt.Println(leaders)
-func AllTimeLeadersGrids(leagueID, perMode, seasonType string, topX int) (*client.NBAResponse, error) {
+func AllTimeLeadersGrids(ctx context.Context, session *client.Client, leagueID, perMode, seasonType string, topX int) (*client.NBAResponse, error) {
 	if err := validateAllTimeLeadersParams(leagueID, perMode, seasonType, topX); err != nil {
 		return nil, err
 	}
@@ -42,7 +43,7 @@ func AllTimeLeadersGrids(leagueID, perMode, seasonType string, topX int) (*clien
 		"TopX":       fmt.Sprintf("%d", topX),
 	}
 
-	return client.NBASession.NBAGetRequest(endoints.AllTimeLeadersGrids, params, "", nil)
+	return session.NBAGetRequest(ctx, endoints.AllTimeLeadersGrids, params, "", nil)
 }
 
 // validateAllTimeLeadersParams ensures all input parameters are valid.
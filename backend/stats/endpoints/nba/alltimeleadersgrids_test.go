@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -22,7 +23,7 @@ func TestAllTimeLeadersGrids_ActualCall(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := AllTimeLeadersGrids(test.leagueID, test.perMode, test.seasonType, test.topX)
+		resp, err := AllTimeLeadersGrids(context.Background(), newTestSession(t), test.leagueID, test.perMode, test.seasonType, test.topX)
 
 		if test.expectErr {
 			// If we expect an error, ensure an error is returned
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
@@ -26,12 +27,12 @@ import (
 //
 // Example Usage:
 //
-//	leaders, err := AssistLeaders("00", "2023-24", "Regular Season", "PerGame", 10)
+//	leaders, err := AssistLeaders(ctx, "00", "2023-24", "Regular Season", "PerGame", 10)
 //	if err != nil {
 //	    log.Fatal("Error fetching assist leaders:", err)
 //	}
 //	fmt.Println(leaders)
-func AssistLeaders(leagueID, season, seasonType, perMode string, topX int) (*client.NBAResponse, error) {
+func AssistLeaders(ctx context.Context, session *client.Client, leagueID, season, seasonType, perMode string, topX int) (*client.NBAResponse, error) {
 	if err := validateAssistLeadersParams(leagueID, season, seasonType, perMode, topX); err != nil {
 		return nil, err
 	}
@@ -44,7 +45,7 @@ func AssistLeaders(leagueID, season, seasonType, perMode string, topX int) (*cli
 		"TopX":       fmt.Sprintf("%d", topX),
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.AssistLeaders, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.AssistLeaders, params, "", nil)
 }
 
 // validateAssistLeadersParams ensures all input parameters are valid.
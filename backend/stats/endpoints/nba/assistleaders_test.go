@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -24,7 +25,7 @@ func TestAssistLeaders_ActualCall(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := AssistLeaders(test.leagueID, test.season, test.seasonType, test.perMode, test.topX)
+		resp, err := AssistLeaders(context.Background(), newTestSession(t), test.leagueID, test.season, test.seasonType, test.perMode, test.topX)
 
 		if test.expectErr {
 			// If we expect an error, ensure an error is returned
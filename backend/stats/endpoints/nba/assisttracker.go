@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -38,7 +39,7 @@ type AssistTrackerOptions struct {
 	College          string // Filter by college attended (e.g., "Duke", "Kentucky").
 }
 
-func AssistTracker(opts *AssistTrackerOptions) (*client.NBAResponse, error) {
+func AssistTracker(ctx context.Context, session *client.Client, opts *AssistTrackerOptions) (*client.NBAResponse, error) {
 	if err := validateAssistTrackerParams(opts); err != nil {
 		return nil, err
 	}
@@ -74,10 +75,16 @@ func AssistTracker(opts *AssistTrackerOptions) (*client.NBAResponse, error) {
 		"College":          opts.College,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.AssistTracker, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.AssistTracker, params, "", nil)
 }
 
 func validateAssistTrackerParams(opts *AssistTrackerOptions) error {
+	if opts.Season != "" {
+		if valid, err := helpers.ValidateSeason(opts.Season); !valid {
+			return err
+		}
+	}
+
 	if opts.SeasonType != "" {
 		if valid, err := helpers.ValidateSeasonType(opts.SeasonType); !valid {
 			return err
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -59,7 +60,7 @@ func TestAssistTracker_ActualCall(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := AssistTracker(&test.opts)
+		resp, err := AssistTracker(context.Background(), newTestSession(t), &test.opts)
 
 		if test.expectErr {
 			// If we expect an error, ensure an error is returned
@@ -1,11 +1,15 @@
 package nba
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"sports_api/apierror"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
+	"sports_api/tracing"
 	endoints "sports_api/urls/nba"
 )
 
@@ -30,12 +34,12 @@ import (
 //
 // Example Usage:
 //
-//	players, err := CommonAllPlayers(1, "00", "2023-24")
+//	players, err := CommonAllPlayers(ctx, 1, "00", "2023-24")
 //	if err != nil {
 //	    log.Fatal("Error fetching players:", err)
 //	}
 //	fmt.Println(players)
-func CommonAllPlayers(isOnlyCurrentSeason int, leagueID, season string) (*client.NBAResponse, error) {
+func CommonAllPlayers(ctx context.Context, session *client.Client, isOnlyCurrentSeason int, leagueID, season string) (*client.NBAResponse, error) {
 
 	if err := validateCommonAllPlayersParams(isOnlyCurrentSeason, leagueID, season); err != nil {
 		return nil, err
@@ -47,7 +51,7 @@ func CommonAllPlayers(isOnlyCurrentSeason int, leagueID, season string) (*client
 		"Season":              season,
 	}
 
-	return client.NBASession.NBAGetRequest(endoints.CommonAllPlayer, params, "", nil)
+	return session.NBAGetRequest(ctx, endoints.CommonAllPlayer, params, "", nil)
 }
 
 type Player struct {
@@ -97,7 +101,7 @@ func (p *Player) SetOutcome(bookmaker, outcomeType, name string, point float64,
 
 func (p *Player) SetCurrentSeasonLogs(seasonLog BaseGameLogSlice) *Player {
 	if len(seasonLog) == 0 || seasonLog == nil {
-		fmt.Println("No logs found for ", p.Name)
+		slog.Debug("no season logs found", "player", p.Name)
 		return nil
 	}
 	p.CurrentSeasonLogs = seasonLog
@@ -123,7 +127,6 @@ func americanOddsToProbability(odds int) float64 {
 func calculateArbitrageBets(price1, price2 int, totalBankroll float64) (bool, float64, float64, float64) {
 	prob1 := americanOddsToProbability(price1)
 	prob2 := americanOddsToProbability(price2)
-	fmt.Println(prob1, prob2)
 	// Arbitrage condition: 1/prob1 + 1/prob2 < 1
 	arbValue := (1/prob1 + 1/prob2)
 	if arbValue >= 1 {
@@ -138,55 +141,38 @@ func calculateArbitrageBets(price1, price2 int, totalBankroll float64) (bool, fl
 	return true, stake1, stake2, profitPercentage
 }
 
-func GetAllNBAPlayers() []Player {
-	players, err := CommonAllPlayers(1, "00", "2024-25")
-	if err != nil {
-		return nil
-	}
-
-	dict2, err := players.GetNormalizedDict2()
-
-	if err != nil {
-		return nil
-	}
-	currentSeasonPlayers := dict2["CommonAllPlayers"]
-	jsonData, err := json.Marshal(currentSeasonPlayers)
-	var player []Player
-	err = json.Unmarshal(jsonData, &player)
-	if err != nil {
-		fmt.Println(err)
-	}
-	return player
-
+// GetAllNBAPlayers returns the players on an NBA roster this season.
+func GetAllNBAPlayers(ctx context.Context, session *client.Client) ([]Player, error) {
+	return getCurrentPlayers(ctx, session, "00", session.Season)
 }
 
-func GetAllWNBAPlayers() []Player {
-	players, err := CommonAllPlayers(1, "10", "2024-25")
-	if err != nil {
-		return nil
-	}
+// GetAllWNBAPlayers returns the players on a WNBA roster this season.
+func GetAllWNBAPlayers(ctx context.Context, session *client.Client) ([]Player, error) {
+	return getCurrentPlayers(ctx, session, "10", session.WNBASeason)
+}
 
-	dict2, err := players.GetNormalizedDict2()
+func getCurrentPlayers(ctx context.Context, session *client.Client, leagueID, season string) (_ []Player, err error) {
+	ctx, span := tracing.Start(ctx, "endpoints/nba.getCurrentPlayers", trace.WithAttributes(attribute.String("league_id", leagueID), attribute.String("season", season)))
+	defer tracing.End(span, &err)
 
+	players, err := CommonAllPlayers(ctx, session, 1, leagueID, season)
 	if err != nil {
-		return nil
+		return nil, err
 	}
-	currentSeasonPlayers := dict2["CommonAllPlayers"]
-	jsonData, err := json.Marshal(currentSeasonPlayers)
-	var player []Player
-	err = json.Unmarshal(jsonData, &player)
+
+	rows, mismatch, err := client.DecodeResultSet[Player](players, "CommonAllPlayers")
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	return player
-
+	mismatch.Log(ctx)
+	return rows, nil
 }
 
 // validateCommonAllPlayersParams ensures all input parameters are valid.
 func validateCommonAllPlayersParams(isOnlyCurrentSeason int, leagueID, season string) error {
 	// Validate isOnlyCurrentSeason (must be 0 or 1)
 	if isOnlyCurrentSeason != 0 && isOnlyCurrentSeason != 1 {
-		return errors.New("invalid value for IsOnlyCurrentSeason: must be 0 (all players) or 1 (current season only)")
+		return apierror.New(apierror.Validation, "invalid value for IsOnlyCurrentSeason: must be 0 (all players) or 1 (current season only)")
 	}
 
 	// Validate leagueID using helper function
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -23,8 +24,8 @@ func TestCommonAllPlayers_ActualCall(t *testing.T) {
 	}
 
 	for i, test := range tests {
-		resp, err := CommonAllPlayers(test.isOnlyCurrentSeason, test.leagueID, test.season)
-		if test.isOnlyCurrentSeason == 1 {
+		resp, err := CommonAllPlayers(context.Background(), newTestSession(t), test.isOnlyCurrentSeason, test.leagueID, test.season)
+		if test.isOnlyCurrentSeason == 1 && err == nil {
 			fmt.Println(i)
 			fmt.Println(resp.GetNormalizedDict())
 		}
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -22,12 +23,12 @@ import (
 // Example Usage:
 //
 //	league := "00"
-//	playerData, err := CommonPlayerInfo("2544", &league)
+//	playerData, err := CommonPlayerInfo(ctx, "2544", &league)
 //	if err != nil {
 //	    log.Fatal("Error fetching player info:", err)
 //	}
 //	fmt.Println(playerData)
-func CommonPlayerInfo(playerID string, leagueID *string) (*client.NBAResponse, error) {
+func CommonPlayerInfo(ctx context.Context, session *client.Client, playerID string, leagueID *string) (*client.NBAResponse, error) {
 	if err := validateCommonPlayerInfoParams(playerID, leagueID); err != nil {
 		return nil, err
 	}
@@ -41,7 +42,7 @@ func CommonPlayerInfo(playerID string, leagueID *string) (*client.NBAResponse, e
 		params["LeagueID"] = *leagueID
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.CommonPlayerInfo, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.CommonPlayerInfo, params, "", nil)
 }
 
 // validateCommonPlayerInfoParams ensures all input parameters are valid.
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -24,7 +25,7 @@ func TestCommonPlayerInfo_ActualCall(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := CommonPlayerInfo(test.playerID, test.leagueID)
+		resp, err := CommonPlayerInfo(context.Background(), newTestSession(t), test.playerID, test.leagueID)
 
 		if test.expectErr {
 			if err == nil {
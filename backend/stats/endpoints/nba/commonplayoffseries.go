@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -25,12 +26,12 @@ import (
 // Example Usage:
 //
 //	seriesID := "0041900401"
-//	playoffData, err := CommonPlayoffSeries("00", "2019-20", &seriesID)
+//	playoffData, err := CommonPlayoffSeries(ctx, "00", "2019-20", &seriesID)
 //	if err != nil {
 //	    log.Fatal("Error fetching playoff series:", err)
 //	}
 //	fmt.Println(playoffData)
-func CommonPlayoffSeries(leagueID, season string, seriesID *string) (*client.NBAResponse, error) {
+func CommonPlayoffSeries(ctx context.Context, session *client.Client, leagueID, season string, seriesID *string) (*client.NBAResponse, error) {
 	if err := validateCommonPlayoffSeriesParams(leagueID, season); err != nil {
 		return nil, err
 	}
@@ -45,7 +46,7 @@ func CommonPlayoffSeries(leagueID, season string, seriesID *string) (*client.NBA
 		params["SeriesID"] = *seriesID
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.CommonPlayoffSeries, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.CommonPlayoffSeries, params, "", nil)
 }
 
 // validateCommonPlayoffSeriesParams ensures all input parameters are valid.
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -19,15 +20,15 @@ func TestCommonPlayoffSeries_ActualCall(t *testing.T) {
 		seriesID  *string
 		expectErr bool
 	}{
-		{leagueNBA, validSeason, &seriesID, true}, // Valid request (NBA, 2019-20, specific series)
-		{leagueNBA, validSeason, nil, false},      // Valid request (NBA, 2019-20, all series)
-		{"10", validSeason, nil, false},           // Valid request (WNBA, 2019-20)
-		{invalidLeague, validSeason, nil, true},   // Invalid LeagueID
-		{leagueNBA, invalidSeason, nil, true},     // Invalid Season format
+		{leagueNBA, validSeason, &seriesID, false}, // Valid request (NBA, 2019-20, specific series)
+		{leagueNBA, validSeason, nil, false},       // Valid request (NBA, 2019-20, all series)
+		{"10", validSeason, nil, false},            // Valid request (WNBA, 2019-20)
+		{invalidLeague, validSeason, nil, true},    // Invalid LeagueID
+		{leagueNBA, invalidSeason, nil, true},      // Invalid Season format
 	}
 
 	for _, test := range tests {
-		resp, err := CommonPlayoffSeries(test.leagueID, test.season, test.seriesID)
+		resp, err := CommonPlayoffSeries(context.Background(), newTestSession(t), test.leagueID, test.season, test.seriesID)
 
 		if test.expectErr {
 			if err == nil {
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -23,12 +24,12 @@ import (
 // Example Usage:
 //
 //	league := "00"
-//	roster, err := CommonTeamRoster("1610612739", "2019-20", &league)
+//	roster, err := CommonTeamRoster(ctx, "1610612739", "2019-20", &league)
 //	if err != nil {
 //	    log.Fatal("Error fetching team roster:", err)
 //	}
 //	fmt.Println(roster)
-func CommonTeamRoster(teamID, season string, leagueID *string) (*client.NBAResponse, error) {
+func CommonTeamRoster(ctx context.Context, session *client.Client, teamID, season string, leagueID *string) (*client.NBAResponse, error) {
 	if err := validateCommonTeamRosterParams(teamID, season, leagueID); err != nil {
 		return nil, err
 	}
@@ -43,7 +44,7 @@ func CommonTeamRoster(teamID, season string, leagueID *string) (*client.NBARespo
 		params["LeagueID"] = *leagueID
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.CommonTeamRoster, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.CommonTeamRoster, params, "", nil)
 }
 
 // validateCommonTeamRosterParams ensures all input parameters are valid.
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -27,7 +28,7 @@ func TestCommonTeamRoster(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := CommonTeamRoster(test.teamID, test.season, test.leagueID)
+		resp, err := CommonTeamRoster(context.Background(), newTestSession(t), test.teamID, test.season, test.leagueID)
 
 		if test.expectErr {
 			if err == nil {
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -20,12 +21,12 @@ import (
 //
 // Example Usage:
 //
-//	years, err := CommonTeamYears("00")
+//	years, err := CommonTeamYears(ctx, "00")
 //	if err != nil {
 //	    log.Fatal("Error fetching team years:", err)
 //	}
 //	fmt.Println(years)
-func CommonTeamYears(leagueID string) (*client.NBAResponse, error) {
+func CommonTeamYears(ctx context.Context, session *client.Client, leagueID string) (*client.NBAResponse, error) {
 	if err := validateCommonTeamYearsParams(leagueID); err != nil {
 		return nil, err
 	}
@@ -34,7 +35,7 @@ func CommonTeamYears(leagueID string) (*client.NBAResponse, error) {
 		"LeagueID": leagueID,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.CommonTeamYears, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.CommonTeamYears, params, "", nil)
 }
 
 // validateCommonTeamYearsParams ensures the leagueID is valid.
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -21,7 +22,7 @@ func TestCommonTeamYears(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := CommonTeamYears(test.leagueID)
+		resp, err := CommonTeamYears(context.Background(), newTestSession(t), test.leagueID)
 
 		if test.expectErr {
 			if err == nil {
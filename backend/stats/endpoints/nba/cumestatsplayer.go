@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -26,7 +27,7 @@ import (
 //	    log.Fatal("Error fetching cumulative player stats:", err)
 //	}
 //	fmt.Println(stats)
-func CumulativeStatsPlayer(gameIDs, leagueID, playerID, season, seasonType string) (*client.NBAResponse, error) {
+func CumulativeStatsPlayer(ctx context.Context, session *client.Client, gameIDs, leagueID, playerID, season, seasonType string) (*client.NBAResponse, error) {
 	if err := validateCumulativeStatsPlayerParams(gameIDs, leagueID, playerID, season, seasonType); err != nil {
 		return nil, err
 	}
@@ -39,7 +40,7 @@ func CumulativeStatsPlayer(gameIDs, leagueID, playerID, season, seasonType strin
 		"SeasonType": seasonType,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.CumulativeStatsPlayer, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.CumulativeStatsPlayer, params, "", nil)
 }
 
 // validateCumulativeStatsPlayerParams ensures all input parameters are valid.
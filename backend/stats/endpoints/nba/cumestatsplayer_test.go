@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"testing"
 )
 
@@ -32,7 +33,7 @@ func TestCumulativeStatsPlayer(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		_, err := CumulativeStatsPlayer(test.gameIDs, test.leagueID, test.playerID, test.season, test.seasonType)
+		_, err := CumulativeStatsPlayer(context.Background(), newTestSession(t), test.gameIDs, test.leagueID, test.playerID, test.season, test.seasonType)
 
 		if test.expectErr {
 			if err == nil {
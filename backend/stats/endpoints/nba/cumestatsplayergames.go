@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -41,7 +42,7 @@ type CumeStatsPlayerGamesOptions struct {
 //	    log.Fatal("Error fetching cumulative player game stats:", err)
 //	}
 //	fmt.Println(stats)
-func CumulativeStatsPlayerGames(opts CumeStatsPlayerGamesOptions) (*client.NBAResponse, error) {
+func CumulativeStatsPlayerGames(ctx context.Context, session *client.Client, opts CumeStatsPlayerGamesOptions) (*client.NBAResponse, error) {
 	if err := validateCumulativeStatsPlayerGamesParams(opts); err != nil {
 		return nil, err
 	}
@@ -70,7 +71,7 @@ func CumulativeStatsPlayerGames(opts CumeStatsPlayerGamesOptions) (*client.NBARe
 		params["Location"] = opts.Location
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.CumulativeStatsPlayerGames, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.CumulativeStatsPlayerGames, params, "", nil)
 }
 
 // validateCumulativeStatsPlayerGamesParams ensures all input parameters are valid.
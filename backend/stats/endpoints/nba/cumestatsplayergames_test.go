@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -46,7 +47,7 @@ func TestCumulativeStatsPlayerGames(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := CumulativeStatsPlayerGames(test.opts)
+		resp, err := CumulativeStatsPlayerGames(context.Background(), newTestSession(t), test.opts)
 		if test.expectErr {
 			if err == nil {
 				t.Errorf("Expected error but got nil for input: %+v", test.opts)
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -26,7 +27,7 @@ import (
 //	    log.Fatal("Error fetching cumulative team stats:", err)
 //	}
 //	fmt.Println(stats)
-func CumulativeStatsTeam(gameIDs, leagueID, season, seasonType, teamID string) (*client.NBAResponse, error) {
+func CumulativeStatsTeam(ctx context.Context, session *client.Client, gameIDs, leagueID, season, seasonType, teamID string) (*client.NBAResponse, error) {
 	// Validate input parameters
 	if err := validateCumulativeStatsTeamParams(gameIDs, leagueID, season, seasonType, teamID); err != nil {
 		return nil, err
@@ -40,7 +41,7 @@ func CumulativeStatsTeam(gameIDs, leagueID, season, seasonType, teamID string) (
 		"TeamID":     teamID,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.CumulativeStatsTeam, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.CumulativeStatsTeam, params, "", nil)
 }
 
 // validateCumeStatsTeamParams ensures all input parameters are valid.
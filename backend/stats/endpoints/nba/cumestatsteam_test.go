@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -32,7 +33,7 @@ func TestCumulativeStatsTeam(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := CumulativeStatsTeam(test.gameIDs, test.leagueID, test.season, test.seasonType, test.teamID)
+		resp, err := CumulativeStatsTeam(context.Background(), newTestSession(t), test.gameIDs, test.leagueID, test.season, test.seasonType, test.teamID)
 
 		if test.expectErr {
 			if err == nil {
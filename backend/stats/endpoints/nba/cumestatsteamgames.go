@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -21,7 +22,7 @@ type CumulativeStatsTeamGamesOptions struct {
 }
 
 // CumulativeStatsTeamGames calls the NBA API and retrieves cumulative team game statistics based on the provided filters.
-func CumulativeStatsTeamGames(opts CumulativeStatsTeamGamesOptions) (*client.NBAResponse, error) {
+func CumulativeStatsTeamGames(ctx context.Context, session *client.Client, opts CumulativeStatsTeamGamesOptions) (*client.NBAResponse, error) {
 	// Validate input parameters
 	if err := validateCumulativeStatsTeamGamesParams(opts); err != nil {
 		return nil, err
@@ -54,7 +55,7 @@ func CumulativeStatsTeamGames(opts CumulativeStatsTeamGamesOptions) (*client.NBA
 		params["Location"] = opts.Location
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.CumulativeStatsTeamGames, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.CumulativeStatsTeamGames, params, "", nil)
 }
 
 // validateCumulativeStatsTeamGamesParams ensures all input parameters are valid.
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -29,7 +30,7 @@ func TestCumulativeStatsTeamGames_ActualCall(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := CumulativeStatsTeamGames(test.opts)
+		resp, err := CumulativeStatsTeamGames(context.Background(), newTestSession(t), test.opts)
 
 		if test.expectErr {
 			if err == nil {
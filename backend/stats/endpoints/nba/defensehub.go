@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -21,7 +22,7 @@ type DefenseHubOptions struct {
 //
 // Example Usage:
 //
-//	data, err := DefenseHub(DefenseHubOptions{
+//	data, err := DefenseHub(ctx, DefenseHubOptions{
 //	    GameScope:   "Season",
 //	    LeagueID:    "00",
 //	    PlayerOrTeam: "Team",
@@ -33,7 +34,7 @@ type DefenseHubOptions struct {
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(data)
-func DefenseHub(opts DefenseHubOptions) (*client.NBAResponse, error) {
+func DefenseHub(ctx context.Context, session *client.Client, opts DefenseHubOptions) (*client.NBAResponse, error) {
 	// Validate parameters
 	if err := validateDefenseHubParams(opts); err != nil {
 		return nil, err
@@ -48,13 +49,13 @@ func DefenseHub(opts DefenseHubOptions) (*client.NBAResponse, error) {
 		"Season":       opts.Season,
 		"SeasonType":   opts.SeasonType,
 	}
-	//request, err := client.NBASession.NBAGetRequest(endpoints.DefenseHub, params, "", nil)
+	//request, err := session.NBAGetRequest(ctx, endpoints.DefenseHub, params, "", nil)
 	//if err != nil {
 	//	return nil, err
 	//}
 
 	// Make API request
-	return client.NBASession.NBAGetRequest(endpoints.DefenseHub, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.DefenseHub, params, "", nil)
 }
 
 // validateDefenseHubParams ensures all input parameters are valid.
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -11,15 +12,15 @@ func TestDefenseHub_ActualCall(t *testing.T) {
 		options   DefenseHubOptions
 		expectErr bool
 	}{
-		{DefenseHubOptions{"Season", "00", "Team", "All Players", "2019-20", "Regular Season"}, false},   // Valid request
-		{DefenseHubOptions{"Finals", "00", "Player", "Rookies", "2019-20", "Playoffs"}, false},           // Valid request
-		{DefenseHubOptions{"Last 10", "99", "Team", "All Players", "2019-20", "Regular Season"}, true},   // Invalid LeagueID
-		{DefenseHubOptions{"Season", "00", "Unknown", "All Players", "2019-20", "Regular Season"}, true}, // Invalid PlayerOrTeam
-		{DefenseHubOptions{"Yesterday", "00", "Team", "All Players", "20-19", "Regular Season"}, true},   // Invalid Season format
+		{DefenseHubOptions{"Yesterday", "00", "Team", "All Players", "2019-20", "Regular Season"}, false}, // Valid request
+		{DefenseHubOptions{"Last 10", "00", "Player", "Rookies", "2019-20", "Playoffs"}, false},           // Valid request
+		{DefenseHubOptions{"Last 10", "99", "Team", "All Players", "2019-20", "Regular Season"}, true},    // Invalid LeagueID
+		{DefenseHubOptions{"Season", "00", "Unknown", "All Players", "2019-20", "Regular Season"}, true},  // Invalid PlayerOrTeam
+		{DefenseHubOptions{"Yesterday", "00", "Team", "All Players", "20-19", "Regular Season"}, true},    // Invalid Season format
 	}
 
 	for _, test := range tests {
-		resp, err := DefenseHub(test.options)
+		resp, err := DefenseHub(context.Background(), newTestSession(t), test.options)
 
 		if test.expectErr {
 			if err == nil {
@@ -1,8 +1,9 @@
 package nba
 
 import (
-	"errors"
+	"context"
 	"regexp"
+	"sports_api/apierror"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -24,7 +25,7 @@ type DraftBoardOptions struct {
 //
 // Example Usage:
 //
-//	data, err := DraftBoard(DraftBoardOptions{
+//	data, err := DraftBoard(ctx, DraftBoardOptions{
 //	    LeagueID: "00",
 //	    Season:   "2019",
 //	})
@@ -32,7 +33,7 @@ type DraftBoardOptions struct {
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(data)
-func DraftBoard(opts DraftBoardOptions) (*client.NBAResponse, error) {
+func DraftBoard(ctx context.Context, session *client.Client, opts DraftBoardOptions) (*client.NBAResponse, error) {
 	// Validate parameters
 	if err := validateDraftBoardParams(opts); err != nil {
 		return nil, err
@@ -65,7 +66,7 @@ func DraftBoard(opts DraftBoardOptions) (*client.NBAResponse, error) {
 	}
 
 	// Make API request
-	return client.NBASession.NBAGetRequest(endpoints.DraftBoard, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.DraftBoard, params, "", nil)
 }
 
 // validateDraftBoardParams ensures all input parameters are valid.
@@ -78,7 +79,7 @@ func validateDraftBoardParams(opts DraftBoardOptions) error {
 	// Validate Season format (must be "YYYY")
 	seasonPattern := `^\d{4}$`
 	if matched, err := regexp.MatchString(seasonPattern, opts.Season); err != nil || !matched {
-		return errors.New("invalid Season format: must be 'YYYY' (e.g., '2019')")
+		return apierror.New(apierror.Validation, "invalid Season format: must be 'YYYY' (e.g., '2019')")
 	}
 
 	return nil
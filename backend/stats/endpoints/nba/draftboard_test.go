@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -40,7 +41,7 @@ func TestDraftBoard(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := DraftBoard(test.opts)
+		resp, err := DraftBoard(context.Background(), newTestSession(t), test.opts)
 		if test.expectErr {
 			if err == nil {
 				t.Errorf("Expected error but got nil for input: %+v", test.opts)
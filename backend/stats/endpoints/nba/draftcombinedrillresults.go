@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -10,12 +11,12 @@ import (
 //
 // Example Usage:
 //
-//	data, err := DraftCombineDrillResults("00", "2019")
+//	data, err := DraftCombineDrillResults(ctx, "00", "2019")
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(data)
-func DraftCombineDrillResults(leagueID, seasonYear string) (*client.NBAResponse, error) {
+func DraftCombineDrillResults(ctx context.Context, session *client.Client, leagueID, seasonYear string) (*client.NBAResponse, error) {
 	// Validate parameters
 	if err := validateDraftCombineDrillResultsParams(leagueID, seasonYear); err != nil {
 		return nil, err
@@ -28,7 +29,7 @@ func DraftCombineDrillResults(leagueID, seasonYear string) (*client.NBAResponse,
 	}
 
 	// Make API request
-	return client.NBASession.NBAGetRequest(endpoints.DraftCombineDrillResults, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.DraftCombineDrillResults, params, "", nil)
 }
 
 // validateDraftCombineDrillResultsParams ensures all input parameters are valid.
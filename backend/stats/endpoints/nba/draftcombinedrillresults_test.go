@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -19,7 +20,7 @@ func TestDraftCombineDrillResults(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := DraftCombineDrillResults(test.leagueID, test.seasonYear)
+		resp, err := DraftCombineDrillResults(context.Background(), newTestSession(t), test.leagueID, test.seasonYear)
 
 		if test.expectErr {
 			if err == nil {
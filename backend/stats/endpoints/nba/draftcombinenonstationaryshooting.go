@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -10,12 +11,12 @@ import (
 //
 // Example Usage:
 //
-//	data, err := DraftCombineNonStationaryShooting("00", "2019")
+//	data, err := DraftCombineNonStationaryShooting(ctx, "00", "2019")
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(data)
-func DraftCombineNonStationaryShooting(leagueID, seasonYear string) (*client.NBAResponse, error) {
+func DraftCombineNonStationaryShooting(ctx context.Context, session *client.Client, leagueID, seasonYear string) (*client.NBAResponse, error) {
 	// Validate parameters
 	if err := validateDraftCombineNonStationaryShootingParams(leagueID, seasonYear); err != nil {
 		return nil, err
@@ -28,7 +29,7 @@ func DraftCombineNonStationaryShooting(leagueID, seasonYear string) (*client.NBA
 	}
 
 	// Make API request
-	return client.NBASession.NBAGetRequest(endpoints.DraftCombineNonStationaryShooting, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.DraftCombineNonStationaryShooting, params, "", nil)
 }
 
 // validateDraftCombineNonStationaryShootingParams ensures all input parameters are valid.
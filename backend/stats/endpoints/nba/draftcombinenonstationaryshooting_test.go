@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"testing"
 )
 
@@ -20,7 +21,7 @@ func TestDraftCombineNonStationaryShooting(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		_, err := DraftCombineNonStationaryShooting(test.leagueID, test.seasonYear)
+		_, err := DraftCombineNonStationaryShooting(context.Background(), newTestSession(t), test.leagueID, test.seasonYear)
 		if test.expectErr {
 			if err == nil {
 				t.Errorf("Expected error but got nil for input: %+v", test)
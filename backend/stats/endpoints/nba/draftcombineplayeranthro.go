@@ -1,13 +1,14 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
 )
 
 // DraftCombinePlayerAnthro calls the NBA API and retrieves player anthropometric data.
-func DraftCombinePlayerAnthro(leagueID, seasonYear string) (*client.NBAResponse, error) {
+func DraftCombinePlayerAnthro(ctx context.Context, session *client.Client, leagueID, seasonYear string) (*client.NBAResponse, error) {
 	// Validate parameters
 	if err := validateDraftCombineParams(leagueID, seasonYear); err != nil {
 		return nil, err
@@ -20,7 +21,7 @@ func DraftCombinePlayerAnthro(leagueID, seasonYear string) (*client.NBAResponse,
 	}
 
 	// Make API request
-	return client.NBASession.NBAGetRequest(endpoints.DraftCombinePlayerAnthro, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.DraftCombinePlayerAnthro, params, "", nil)
 }
 
 // validateDraftCombineParams ensures leagueID and seasonYear are valid.
@@ -1,6 +1,9 @@
 package nba
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 // TestDraftCombinePlayerAnthro validates correct and incorrect parameters.
 func TestDraftCombinePlayerAnthro(t *testing.T) {
@@ -18,7 +21,7 @@ func TestDraftCombinePlayerAnthro(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		_, err := DraftCombinePlayerAnthro(test.leagueID, test.seasonYear)
+		_, err := DraftCombinePlayerAnthro(context.Background(), newTestSession(t), test.leagueID, test.seasonYear)
 		if test.expectErr {
 			if err == nil {
 				t.Errorf("Expected error but got nil for input: %+v", test)
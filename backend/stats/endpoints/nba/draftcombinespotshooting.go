@@ -1,13 +1,14 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
 )
 
 // DraftCombineSpotShooting retrieves spot shooting stats for NBA draft prospects.
-func DraftCombineSpotShooting(leagueID, seasonYear string) (*client.NBAResponse, error) {
+func DraftCombineSpotShooting(ctx context.Context, session *client.Client, leagueID, seasonYear string) (*client.NBAResponse, error) {
 	// Validate input parameters
 	if valid, err := helpers.ValidateLeagueID(leagueID); !valid {
 		return nil, err
@@ -21,5 +22,5 @@ func DraftCombineSpotShooting(leagueID, seasonYear string) (*client.NBAResponse,
 		"SeasonYear": seasonYear,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.DraftCombineSpotShooting, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.DraftCombineSpotShooting, params, "", nil)
 }
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -25,7 +26,7 @@ func TestDraftCombineSpotShooting(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := DraftCombineSpotShooting(test.leagueID, test.seasonYear)
+		resp, err := DraftCombineSpotShooting(context.Background(), newTestSession(t), test.leagueID, test.seasonYear)
 		if test.expectErr {
 			if err == nil {
 				t.Errorf("Expected error but got nil for input: %+v", test)
@@ -1,13 +1,14 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
 )
 
 // DraftCombineStats retrieves draft combine statistics for NBA draft prospects.
-func DraftCombineStats(leagueID, seasonYear string) (*client.NBAResponse, error) {
+func DraftCombineStats(ctx context.Context, session *client.Client, leagueID, seasonYear string) (*client.NBAResponse, error) {
 	// Validate input parameters
 	if valid, err := helpers.ValidateLeagueID(leagueID); !valid {
 		return nil, err
@@ -21,5 +22,5 @@ func DraftCombineStats(leagueID, seasonYear string) (*client.NBAResponse, error)
 		"SeasonYear": seasonYear,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.DraftCombineStats, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.DraftCombineStats, params, "", nil)
 }
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -27,7 +28,7 @@ func TestDraftCombineStats(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := DraftCombineStats(test.leagueID, test.seasonYear)
+		resp, err := DraftCombineStats(context.Background(), newTestSession(t), test.leagueID, test.seasonYear)
 
 		if test.expectErr {
 			if err == nil {
@@ -1,7 +1,8 @@
 package nba
 
 import (
-	"errors"
+	"context"
+	"sports_api/apierror"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
@@ -34,26 +35,26 @@ func (opts *DraftHistoryOptions) ValidateDraftHistory() error {
 
 	// Ensure no negative values for numerical fields
 	if opts.TopX < 0 {
-		return errors.New("TopX must be a non-negative integer")
+		return apierror.New(apierror.Validation, "TopX must be a non-negative integer")
 	}
 	if opts.RoundPick < 0 {
-		return errors.New("RoundPick must be a non-negative integer")
+		return apierror.New(apierror.Validation, "RoundPick must be a non-negative integer")
 	}
 	if opts.RoundNum < 0 {
-		return errors.New("RoundNum must be a non-negative integer")
+		return apierror.New(apierror.Validation, "RoundNum must be a non-negative integer")
 	}
 	if opts.OverallPick < 0 {
-		return errors.New("OverallPick must be a non-negative integer")
+		return apierror.New(apierror.Validation, "OverallPick must be a non-negative integer")
 	}
 	if opts.College < 0 {
-		return errors.New("college must be a non-negative integer")
+		return apierror.New(apierror.Validation, "college must be a non-negative integer")
 	}
 
 	return nil
 }
 
 // DraftHistory retrieves NBA draft history based on the provided filters.
-func DraftHistory(opts DraftHistoryOptions) (*client.NBAResponse, error) {
+func DraftHistory(ctx context.Context, session *client.Client, opts DraftHistoryOptions) (*client.NBAResponse, error) {
 	if err := opts.ValidateDraftHistory(); err != nil {
 		return nil, err
 	}
@@ -90,6 +91,6 @@ func DraftHistory(opts DraftHistoryOptions) (*client.NBAResponse, error) {
 		params["College"] = strconv.Itoa(opts.College)
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.DraftHistory, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.DraftHistory, params, "", nil)
 
 }
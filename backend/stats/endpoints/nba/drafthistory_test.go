@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -39,7 +40,7 @@ func TestDraftHistory(t *testing.T) {
 	}
 
 	for i, test := range tests {
-		resp, err := DraftHistory(test.opts)
+		resp, err := DraftHistory(context.Background(), newTestSession(t), test.opts)
 		fmt.Println(i)
 		if test.expectErr {
 			if err == nil {
@@ -1,13 +1,14 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
 )
 
 // FranchiseHistory retrieves the history of NBA franchises.
-func FranchiseHistory(leagueID string) (*client.NBAResponse, error) {
+func FranchiseHistory(ctx context.Context, session *client.Client, leagueID string) (*client.NBAResponse, error) {
 	// Validate input parameters
 	if valid, err := helpers.ValidateLeagueID(leagueID); !valid {
 		return nil, err
@@ -17,5 +18,5 @@ func FranchiseHistory(leagueID string) (*client.NBAResponse, error) {
 		"LeagueID": leagueID,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.FranchiseHistory, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.FranchiseHistory, params, "", nil)
 }
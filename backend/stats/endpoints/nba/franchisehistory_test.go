@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -20,7 +21,7 @@ func TestFranchiseHistory(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := FranchiseHistory(test.leagueID)
+		resp, err := FranchiseHistory(context.Background(), newTestSession(t), test.leagueID)
 
 		if test.expectErr {
 			if err == nil {
@@ -1,17 +1,18 @@
 package nba
 
 import (
-	"errors"
+	"context"
+	"sports_api/apierror"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
 )
 
 // FranchiseLeaders retrieves the all-time statistical leaders for a given franchise.
-func FranchiseLeaders(teamID string, leagueID *string) (*client.NBAResponse, error) {
+func FranchiseLeaders(ctx context.Context, session *client.Client, teamID string, leagueID *string) (*client.NBAResponse, error) {
 	// Validate input parameters
 	if teamID == "" {
-		return nil, errors.New("TeamID is required")
+		return nil, apierror.New(apierror.Validation, "TeamID is required")
 	}
 
 	if leagueID != nil {
@@ -28,5 +29,5 @@ func FranchiseLeaders(teamID string, leagueID *string) (*client.NBAResponse, err
 		params["LeagueID"] = *leagueID
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.FranchiseLeaders, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.FranchiseLeaders, params, "", nil)
 }
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -24,7 +25,7 @@ func TestFranchiseLeaders(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := FranchiseLeaders(test.teamID, test.leagueID)
+		resp, err := FranchiseLeaders(context.Background(), newTestSession(t), test.teamID, test.leagueID)
 
 		if test.expectErr {
 			if err == nil {
@@ -1,14 +1,15 @@
 package nba
 
 import (
-	"errors"
+	"context"
+	"sports_api/apierror"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
 )
 
 // FranchisePlayers retrieves player statistics for a given franchise.
-func FranchisePlayers(leagueID, perMode, seasonType, teamID string) (*client.NBAResponse, error) {
+func FranchisePlayers(ctx context.Context, session *client.Client, leagueID, perMode, seasonType, teamID string) (*client.NBAResponse, error) {
 	// Validate required parameters
 	if valid, err := helpers.ValidateLeagueID(leagueID); !valid {
 		return nil, err
@@ -20,7 +21,7 @@ func FranchisePlayers(leagueID, perMode, seasonType, teamID string) (*client.NBA
 		return nil, err
 	}
 	if teamID == "" {
-		return nil, errors.New("TeamID is required")
+		return nil, apierror.New(apierror.Validation, "TeamID is required")
 	}
 
 	params := map[string]string{
@@ -30,5 +31,5 @@ func FranchisePlayers(leagueID, perMode, seasonType, teamID string) (*client.NBA
 		"TeamID":     teamID,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.FranchisePlayers, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.FranchisePlayers, params, "", nil)
 }
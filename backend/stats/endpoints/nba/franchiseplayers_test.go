@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -32,7 +33,7 @@ func TestFranchisePlayers(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := FranchisePlayers(test.leagueID, test.perMode, test.seasonType, test.teamID)
+		resp, err := FranchisePlayers(context.Background(), newTestSession(t), test.leagueID, test.perMode, test.seasonType, test.teamID)
 
 		if test.expectErr {
 			if err == nil {
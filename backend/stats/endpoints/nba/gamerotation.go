@@ -1,13 +1,14 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
 	endpoints "sports_api/urls/nba"
 )
 
 // GameRotation retrieves game rotation data for a given game.
-func GameRotation(gameID string, leagueID *string) (*client.NBAResponse, error) {
+func GameRotation(ctx context.Context, session *client.Client, gameID string, leagueID *string) (*client.NBAResponse, error) {
 	// Validate required parameters
 	if valid, err := helpers.ValidateGameID(gameID); !valid {
 		return nil, err
@@ -28,5 +29,5 @@ func GameRotation(gameID string, leagueID *string) (*client.NBAResponse, error)
 		params["LeagueID"] = *leagueID
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.GameRotation, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.GameRotation, params, "", nil)
 }
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -26,7 +27,7 @@ func TestGameRotation(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := GameRotation(test.gameID, test.leagueID)
+		resp, err := GameRotation(context.Background(), newTestSession(t), test.gameID, test.leagueID)
 
 		if test.expectErr {
 			if err == nil {
@@ -1,18 +1,21 @@
 package nba
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sports_api/apierror"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
+	"sports_api/tracing"
 	endpoints "sports_api/urls/nba"
 )
 
 // PlayerGameLog retrieves game log statistics for a specific player.
-func PlayerGameLog(playerID, season, seasonType string, leagueID *string) (*client.NBAResponse, error) {
+func PlayerGameLog(ctx context.Context, session *client.Client, playerID, season, seasonType string, leagueID *string) (*client.NBAResponse, error) {
 	// Validate required parameters
 	if playerID == "" {
-		return nil, errors.New("PlayerID is required")
+		return nil, apierror.New(apierror.Validation, "PlayerID is required")
 	}
 	if _, e := helpers.ValidateSeason(season); e != nil {
 		return nil, e
@@ -38,7 +41,7 @@ func PlayerGameLog(playerID, season, seasonType string, leagueID *string) (*clie
 		params["LeagueID"] = *leagueID
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.PlayerGameLog, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.PlayerGameLog, params, "", nil)
 }
 
 type GameLog struct {
@@ -67,23 +70,18 @@ type GameLog struct {
 	WL        string  `json:"WL"`
 }
 
-func GetPlayerGameLog(playerID, season, seasonType string, leagueID *string) []GameLog {
-	log, err := PlayerGameLog(playerID, season, seasonType, leagueID)
-	if err != nil {
-		return nil
-	}
-	dict2, err := log.GetNormalizedDict2()
-	marshal, err := json.Marshal(dict2["PlayerGameLog"])
+func GetPlayerGameLog(ctx context.Context, session *client.Client, playerID, season, seasonType string, leagueID *string) (_ []GameLog, err error) {
+	ctx, span := tracing.Start(ctx, "endpoints/nba.GetPlayerGameLog", trace.WithAttributes(attribute.String("player_id", playerID), attribute.String("season", season)))
+	defer tracing.End(span, &err)
+
+	log, err := PlayerGameLog(ctx, session, playerID, season, seasonType, leagueID)
 	if err != nil {
-		return nil
+		return nil, err
 	}
-
-	var GameLogs []GameLog
-
-	err = json.Unmarshal(marshal, &GameLogs)
+	rows, mismatch, err := client.DecodeResultSet[GameLog](log, "PlayerGameLog")
 	if err != nil {
-		return nil
+		return nil, err
 	}
-	return GameLogs
-
+	mismatch.Log(ctx)
+	return rows, nil
 }
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -27,7 +28,7 @@ func TestPlayerGameLog(t *testing.T) {
 	}
 	for i, test := range tests {
 		fmt.Println(i)
-		resp, err := PlayerGameLog(test.playerID, test.season, test.seasonType, test.leagueID)
+		resp, err := PlayerGameLog(context.Background(), newTestSession(t), test.playerID, test.season, test.seasonType, test.leagueID)
 		if resp != nil {
 			fmt.Println(resp.GetNormalizedDict())
 		}
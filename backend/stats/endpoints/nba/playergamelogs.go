@@ -1,12 +1,16 @@
 package nba
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"sports_api/apierror"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
+	"sports_api/tracing"
 	endpoints "sports_api/urls/nba"
 )
 
@@ -37,7 +41,7 @@ type PlayerGameLogsOptions struct {
 }
 
 // PlayerGameLogs retrieves a player's game log statistics from the NBA Stats API
-func PlayerGameLogs(opts *PlayerGameLogsOptions) (*client.NBAResponse, error) {
+func PlayerGameLogs(ctx context.Context, session *client.Client, opts *PlayerGameLogsOptions) (*client.NBAResponse, error) {
 	if opts == nil {
 		return nil, errors.New("options must not be nil")
 	}
@@ -66,16 +70,19 @@ func PlayerGameLogs(opts *PlayerGameLogsOptions) (*client.NBAResponse, error) {
 		"DateFrom":       opts.DateFrom,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.PlayerGameLogs, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.PlayerGameLogs, params, "", nil)
 }
 
 // getNBAPlayerStats is a helper function to get game logs based on GameSegment or Period.
-func getNBAPlayerStats(gameSegment string, period int) BaseGameLogSlice {
-	t, e := PlayerGameLogs(&PlayerGameLogsOptions{
+func getNBAPlayerStats(ctx context.Context, session *client.Client, gameSegment string, period int) (_ BaseGameLogSlice, err error) {
+	ctx, span := tracing.Start(ctx, "endpoints/nba.getNBAPlayerStats", trace.WithAttributes(attribute.String("game_segment", gameSegment), attribute.Int("period", period)))
+	defer tracing.End(span, &err)
+
+	t, err := PlayerGameLogs(ctx, session, &PlayerGameLogsOptions{
 		MeasureType:    "Base",
 		PerMode:        "Totals",
 		LeagueID:       "00",
-		Season:         "2024-25",
+		Season:         session.Season,
 		SeasonType:     "Regular Season",
 		PORound:        0,
 		TeamID:         0,
@@ -94,72 +101,59 @@ func getNBAPlayerStats(gameSegment string, period int) BaseGameLogSlice {
 		ShotClockRange: "",
 		LastNGames:     0,
 	})
-	if e != nil {
-		return nil
-	}
-
-	dict2, err := t.GetNormalizedDict2()
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
-	var GameLogs []NBABaseGameLog
-
-	marshal, err := json.Marshal(dict2["PlayerGameLogs"])
-	if err != nil {
-		return nil
-	}
-	err = json.Unmarshal(marshal, &GameLogs)
+	rows, mismatch, err := client.DecodeResultSet[NBABaseGameLog](t, "PlayerGameLogs")
 	if err != nil {
-		fmt.Println(err)
-		return nil
+		return nil, err
 	}
-	return GameLogs
+	mismatch.Log(ctx)
+	return rows, nil
 }
 
 // GetAllNBAPlayerStatsFullSeason retrieves full game stats for all players in the current season.
-func GetAllNBAPlayerStatsFullSeason() BaseGameLogSlice {
-	return getNBAPlayerStats("", 0)
+func GetAllNBAPlayerStatsFullSeason(ctx context.Context, session *client.Client) (BaseGameLogSlice, error) {
+	return getNBAPlayerStats(ctx, session, "", 0)
 }
 
 // GetNBAPlayerStatsByQuarter retrieves game stats for a specific quarter.
-func GetNBAPlayerStatsByQuarter(quarter int) BaseGameLogSlice {
+func GetNBAPlayerStatsByQuarter(ctx context.Context, session *client.Client, quarter int) (BaseGameLogSlice, error) {
 	if quarter < 1 || quarter > 4 {
-		fmt.Println("Invalid quarter. Please enter a value between 1 and 4.")
-		return nil
+		return nil, apierror.New(apierror.Validation, "invalid quarter %d: must be between 1 and 4", quarter)
 	}
-	return getNBAPlayerStats("", quarter)
+	return getNBAPlayerStats(ctx, session, "", quarter)
 }
 
 // GetNBAPlayerStatsFirstHalf retrieves game stats for the first half.
-func GetNBAPlayerStatsFirstHalf() BaseGameLogSlice {
-	return getNBAPlayerStats("First Half", 0)
+func GetNBAPlayerStatsFirstHalf(ctx context.Context, session *client.Client) (BaseGameLogSlice, error) {
+	return getNBAPlayerStats(ctx, session, "First Half", 0)
 }
 
 // GetNBAPlayerStatsSecondHalf retrieves game stats for the second half.
-func GetNBAPlayerStatsSecondHalf() BaseGameLogSlice {
-	return getNBAPlayerStats("Second Half", 0)
+func GetNBAPlayerStatsSecondHalf(ctx context.Context, session *client.Client) (BaseGameLogSlice, error) {
+	return getNBAPlayerStats(ctx, session, "Second Half", 0)
 }
 
-// GetStats retrieves NBA player statistics based on the input key
-func GetCurrentSeasonStats(period string) BaseGameLogSlice {
+// GetCurrentSeasonStats retrieves NBA player statistics for the given period.
+func GetCurrentSeasonStats(ctx context.Context, session *client.Client, period string) (BaseGameLogSlice, error) {
 	switch period {
 	case "Season":
-		return GetAllNBAPlayerStatsFullSeason()
+		return GetAllNBAPlayerStatsFullSeason(ctx, session)
 	case "1Q":
-		return GetNBAPlayerStatsByQuarter(1)
+		return GetNBAPlayerStatsByQuarter(ctx, session, 1)
 	case "2Q":
-		return GetNBAPlayerStatsByQuarter(2)
+		return GetNBAPlayerStatsByQuarter(ctx, session, 2)
 	case "3Q":
-		return GetNBAPlayerStatsByQuarter(3)
+		return GetNBAPlayerStatsByQuarter(ctx, session, 3)
 	case "4Q":
-		return GetNBAPlayerStatsByQuarter(4)
+		return GetNBAPlayerStatsByQuarter(ctx, session, 4)
 	case "1H":
-		return GetNBAPlayerStatsFirstHalf()
+		return GetNBAPlayerStatsFirstHalf(ctx, session)
 	case "2H":
-		return GetNBAPlayerStatsSecondHalf()
+		return GetNBAPlayerStatsSecondHalf(ctx, session)
 	default:
-		fmt.Println("Invalid key. Use 'full', '1Q', '2Q', '3Q', '4Q', '1H', or '2H'.")
-		return nil
+		return nil, apierror.New(apierror.Validation, "invalid period %q: use 'Season', '1Q', '2Q', '3Q', '4Q', '1H' or '2H'", period)
 	}
 }
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -78,10 +79,10 @@ type BaseStatGameLog struct {
 }
 
 func TestGetAllNBAPlayerStats(t *testing.T) {
-	response := GetNBAPlayerStatsSecondHalf()
+	response, err := GetNBAPlayerStatsSecondHalf(context.Background(), newTestSession(t))
 
-	if response == nil {
-		t.Fatal("Expected response, got nil")
+	if err != nil {
+		t.Fatalf("Expected response, got error: %v", err)
 	}
 
 	// Additional checks depending on response structure
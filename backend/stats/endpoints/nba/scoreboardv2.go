@@ -1,15 +1,17 @@
 package nba
 
 import (
+	"context"
 	client "sports_api/globals/nba"
 	helpers "sports_api/helpers/nba"
+	"sports_api/tracing"
 	endpoints "sports_api/urls/nba"
 	"strconv"
 	"time"
 )
 
 // ScoreboardV2 retrieves NBA game data for a specific date.
-func ScoreboardV2(dayOffset int, gameDate *time.Time, leagueID string) (*client.NBAResponse, error) {
+func ScoreboardV2(ctx context.Context, session *client.Client, dayOffset int, gameDate *time.Time, leagueID string) (*client.NBAResponse, error) {
 	// Validate required parameters
 	if valid, err := helpers.ValidateLeagueID(leagueID); !valid {
 		return nil, err
@@ -24,17 +26,23 @@ func ScoreboardV2(dayOffset int, gameDate *time.Time, leagueID string) (*client.
 		"LeagueID":  leagueID,
 	}
 
-	return client.NBASession.NBAGetRequest(endpoints.ScoreboardV2, params, "", nil)
+	return session.NBAGetRequest(ctx, endpoints.ScoreboardV2, params, "", nil)
 }
 
-func GetNBAGamesToday() []map[string]interface{} {
+func GetNBAGamesToday(ctx context.Context, session *client.Client) (_ []map[string]interface{}, err error) {
+	ctx, span := tracing.Start(ctx, "endpoints/nba.GetNBAGamesToday")
+	defer tracing.End(span, &err)
+
 	gameDate := time.Now()
-	scoreBoard, err := ScoreboardV2(0, &gameDate, "00")
+	scoreBoard, err := ScoreboardV2(ctx, session, 0, &gameDate, "00")
+	if err != nil {
+		return nil, err
+	}
 
 	dict2, err := scoreBoard.GetNormalizedDict2()
 	if err != nil {
-		return nil
+		return nil, err
 	}
-	return dict2["GameHeader"]
+	return dict2["GameHeader"], nil
 
 }
@@ -1,6 +1,7 @@
 package nba
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -31,7 +32,7 @@ func TestScoreboardV2(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		resp, err := ScoreboardV2(test.dayOffset, test.gameDate, test.leagueID)
+		resp, err := ScoreboardV2(context.Background(), newTestSession(t), test.dayOffset, test.gameDate, test.leagueID)
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v for input: %+v", err, test)
@@ -0,0 +1,19 @@
+package nba
+
+import (
+	"sports_api/config"
+	"sports_api/fakenba"
+	client "sports_api/globals/nba"
+	"testing"
+)
+
+// newTestSession returns an NBA client with the default configuration, pointed
+// at a fake stats.nba.com that lives as long as t.
+func newTestSession(t *testing.T) *client.Client {
+	t.Helper()
+	server := fakenba.NewServer()
+	t.Cleanup(server.Close)
+	cfg := config.Default()
+	cfg.NBA.BaseURL = server.BaseURL()
+	return client.NewNBAClient(cfg, nil)
+}
@@ -1,6 +1,7 @@
 package nhl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sports_api/globals/nhl"
@@ -53,10 +54,8 @@ func (p *Player) SetOutcome(bookmaker, outcomeType, name string, point float64,
 }
 
 // GetNHLRoster SeasonID in form 20242025
-func (t *NHLTeam) GetRoster(seasonID string) error {
-	nhl.NHLSession.SetBaseUrl("https://api-web.nhle.com/v1/")
-
-	resp, err := nhl.NHLSession.NHLGetRequest(fmt.Sprintf("roster/%s/%s", t.Abbreviation, seasonID), nil, "", nil)
+func (t *NHLTeam) GetRoster(ctx context.Context, session *nhl.Client, seasonID string) error {
+	resp, err := session.WebRequest(ctx, fmt.Sprintf("roster/%s/%s", t.Abbreviation, seasonID), nil, "", nil)
 	if err != nil {
 		return err
 	}
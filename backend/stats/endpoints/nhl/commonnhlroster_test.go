@@ -1,19 +1,25 @@
 package nhl
 
 import (
+	"context"
 	"testing"
 )
 
 func TestGetNHLRoster(t *testing.T) {
-	// Call the function that hits the real NHL API endpoint
-	teams, err := GetAndParseNHLTeams()
+	teams, err := GetAndParseNHLTeams(context.Background(), newTestSession(t))
 	if err != nil {
-		return
+		t.Fatalf("Error calling GetAndParseNHLTeams: %v", err)
 	}
 
-	// Optionally, you can add more checks here to validate
-	// certain fields in resp if you know what shape the data
-	// should have (e.g., length of teams, etc.)
-	t.Logf("Successfully fetched NHL teams. Response: %+v", teams)
-
+	bruins := teams.GetTeamByFullName("Boston Bruins")
+	if bruins == nil {
+		t.Fatal("Expected the Boston Bruins, got nil")
+	}
+	if bruins.Abbreviation != "BOS" {
+		t.Errorf("Abbreviation = %q, want BOS", bruins.Abbreviation)
+	}
+	if len(bruins.Roster) == 0 {
+		t.Fatal("Expected a roster, got none")
+	}
+	t.Logf("Successfully fetched NHL roster. Response: %+v", bruins.Roster)
 }
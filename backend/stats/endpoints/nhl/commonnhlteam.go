@@ -1,8 +1,12 @@
 package nhl
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"sports_api/globals/nhl"
+	"sports_api/logging"
+	"sports_api/tracing"
 	"strings"
 	"sync"
 )
@@ -58,13 +62,12 @@ type NHLTeam struct {
 	Roster         []Player
 }
 
-func GetNHlTeams() (*nhl.NHLResponse, error) {
-	nhl.NHLSession.ResetBaseURL()
+func GetNHlTeams(ctx context.Context, session *nhl.Client) (*nhl.NHLResponse, error) {
 	params := map[string]string{
 		"include": "lastSeason.id",
 	}
 
-	return nhl.NHLSession.NHLGetRequest("stats/rest/en/franchise", params, "", nil)
+	return session.StatsRequest(ctx, "en/franchise", params, "", nil)
 }
 
 type NHLTeams []*NHLTeam
@@ -88,8 +91,11 @@ func (t *NHLTeam) GetPlayerByFullName(fullName string) *Player {
 	return nil
 }
 
-func GetAndParseNHLTeams() (NHLTeams, error) {
-	teams, err := GetNHlTeams()
+func GetAndParseNHLTeams(ctx context.Context, session *nhl.Client) (_ NHLTeams, err error) {
+	ctx, span := tracing.Start(ctx, "endpoints/nhl.GetAndParseNHLTeams")
+	defer tracing.End(span, &err)
+
+	teams, err := GetNHlTeams(ctx, session)
 	if err != nil {
 		return nil, err
 	}
@@ -108,26 +114,35 @@ func GetAndParseNHLTeams() (NHLTeams, error) {
 			m2 := teamData.(map[string]interface{})
 			if m2["lastSeason"] == nil {
 
+				id, err := m2["id"].(json.Number).Int64()
+				if err != nil {
+					logging.FromContext(ctx).WarnContext(ctx, "invalid NHL team ID", "error", err)
+					return
+				}
+
 				fullName := fmt.Sprintf("%v", m2["fullName"])
 				if strings.Contains(fullName, "St.") {
 					fullName = strings.ReplaceAll(fullName, "St.", "St")
 				}
 				team := &NHLTeam{
 					FullName:       fullName,
-					Id:             int(m2["id"].(float64)),
+					Id:             int(id),
 					TeamCommonName: fmt.Sprintf("%v", m2["teamCommonName"]),
 					TeamPlaceName:  fmt.Sprintf("%v", m2["teamPlaceName"]),
 				}
 				team.SetAbbreviation()
-				err := team.GetRoster("20242025")
+				err = team.GetRoster(ctx, session, session.Season)
 				if err != nil {
-					fmt.Println("Could not set roster for team", team.TeamCommonName, team.TeamPlaceName)
+					logging.FromContext(ctx).WarnContext(ctx, "could not set roster for team", "team", team.FullName, "error", err)
 				}
 
 				for i := range team.Roster {
-					err := team.Roster[i].GetGameLog("20242025", 2)
+					if ctx.Err() != nil {
+						break
+					}
+					err := team.Roster[i].GetGameLog(ctx, session, session.Season, 2)
 					if err != nil {
-						fmt.Println("failed to get Gamelog for player")
+						logging.FromContext(ctx).WarnContext(ctx, "failed to get game log for player", "team", team.FullName, "error", err)
 					}
 				}
 
@@ -140,5 +155,8 @@ func GetAndParseNHLTeams() (NHLTeams, error) {
 
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return activeTeams, nil
 }
@@ -1,10 +1,13 @@
 package nhl
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func TestGetNHLTeams(t *testing.T) {
-	// Call the function that hits the real NHL API endpoint
-	resp, err := GetAndParseNHLTeams()
+	// Call the function against the fake NHL hosts
+	resp, err := GetAndParseNHLTeams(context.Background(), newTestSession(t))
 	if err != nil {
 		t.Fatalf("Error calling GetNHLTeams: %v", err)
 	}
@@ -0,0 +1,28 @@
+package nhl
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetAndParseNHLTeamsUsesBothHosts runs the concurrent team, roster and
+// game log fetches against fake hosts; run it with -race.
+func TestGetAndParseNHLTeamsUsesBothHosts(t *testing.T) {
+	teams, err := GetAndParseNHLTeams(context.Background(), newTestSession(t))
+	if err != nil {
+		t.Fatalf("GetAndParseNHLTeams returned error: %v", err)
+	}
+	if len(teams) != len(fakeTeams) {
+		t.Fatalf("GetAndParseNHLTeams returned %d teams, want %d", len(teams), len(fakeTeams))
+	}
+	for _, team := range teams {
+		if len(team.Roster) != 3 {
+			t.Errorf("%s roster has %d players, want 3", team.FullName, len(team.Roster))
+		}
+		for _, player := range team.Roster {
+			if len(player.GameLogs)+len(player.GoalieLogs) != 1 {
+				t.Errorf("%s player %d has no game log", team.FullName, player.Id)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package nhl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sports_api/globals/nhl"
@@ -46,11 +47,11 @@ type GoalieGameLog struct {
 	Toi            string  `json:"toi"`
 }
 
-func (p *Player) GetGameLog(seasonYear string, seasonType int) error {
-
-	nhl.NHLSession.SetBaseUrl("https://api-web.nhle.com/v1/")
-
-	resp, _ := nhl.NHLSession.NHLGetRequest(fmt.Sprintf("player/%d/game-log/%s/%d", p.Id, seasonYear, seasonType), nil, "", nil)
+func (p *Player) GetGameLog(ctx context.Context, session *nhl.Client, seasonYear string, seasonType int) error {
+	resp, err := session.WebRequest(ctx, fmt.Sprintf("player/%d/game-log/%s/%d", p.Id, seasonYear, seasonType), nil, "", nil)
+	if err != nil {
+		return err
+	}
 
 	marsh, err := json.Marshal(resp.Data.(map[string]interface{})["gameLog"])
 	if err != nil {
@@ -0,0 +1,52 @@
+package nhl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sports_api/config"
+	"sports_api/globals/nhl"
+	"strings"
+	"testing"
+)
+
+// fakeTeams are the active franchises the fake stats host lists.
+var fakeTeams = []string{"Boston Bruins", "Toronto Maple Leafs", "Seattle Kraken", "Vegas Golden Knights"}
+
+// newTestSession returns an NHL client with the default configuration, pointed
+// at fake stats and web hosts that live as long as t. Every team has three
+// players and every player one game.
+func newTestSession(t *testing.T) *nhl.Client {
+	t.Helper()
+	stats := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats/rest/en/franchise" {
+			t.Errorf("stats host got %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		var teams []string
+		for id, name := range fakeTeams {
+			teams = append(teams, fmt.Sprintf(`{"id": %d, "fullName": %q, "teamCommonName": "x", "teamPlaceName": "y"}`, id+1, name))
+		}
+		fmt.Fprintf(w, `{"data": [%s]}`, strings.Join(teams, ","))
+	}))
+	t.Cleanup(stats.Close)
+
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1/roster/"):
+			fmt.Fprint(w, `{"forwards": [{"id": 1, "positionCode": "C"}, {"id": 2, "positionCode": "L"}], "goalies": [{"id": 3, "positionCode": "G"}]}`)
+		case strings.HasPrefix(r.URL.Path, "/v1/player/"):
+			fmt.Fprint(w, `{"gameLog": [{"gameId": 1, "goals": 1}]}`)
+		default:
+			t.Errorf("web host got %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(web.Close)
+
+	cfg := config.Default()
+	cfg.NHL.BaseURL = stats.URL + "/stats/rest/"
+	cfg.NHL.WebBaseURL = web.URL + "/v1/"
+	return nhl.NewNHLClient(cfg, nil)
+}
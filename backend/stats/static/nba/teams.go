@@ -1,10 +1,15 @@
 package nba
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	client "sports_api/globals/nba"
+	oddsclient "sports_api/globals/odds"
+	"sports_api/logging"
 	odds "sports_api/odds/nba"
 	models "sports_api/stats/endpoints/nba"
+	"sports_api/tracing"
 	"strings"
 )
 
@@ -106,27 +111,32 @@ func GetWNBATeams() Teams {
 }
 
 // GetWNBATeamsWithPlayers returns a hardcoded list of WNBA teams with their Roster
-func GetWNBATeamsWithPlayers() Teams {
+func GetWNBATeamsWithPlayers(ctx context.Context, session *client.Client) (_ Teams, err error) {
+	ctx, span := tracing.Start(ctx, "static/nba.GetWNBATeamsWithPlayers")
+	defer tracing.End(span, &err)
+
 	wnbaTeams := GetWNBATeams()
-	players := models.GetAllWNBAPlayers()
-	if players == nil || len(players) == 0 {
-		return wnbaTeams
+	players, err := models.GetAllWNBAPlayers(ctx, session)
+	if err != nil {
+		return nil, err
 	}
 	for _, player := range players {
-		fmt.Println(player.TeamID)
-		wnbaTeams.GetTeamByID(player.TeamID).addRosterMember(player)
+		if team := wnbaTeams.GetTeamByID(player.TeamID); team != nil {
+			team.addRosterMember(player)
+		}
 	}
-	fmt.Println(wnbaTeams)
-	return wnbaTeams
+	return wnbaTeams, nil
 }
 
 // GetNNBATeamsWithPlayers returns a hardcoded list of WNBA teams with their Roster
-func GetNBATeamsWithPlayers() Teams {
-	nbaTeams := GetNBATeams()
-	players := models.GetAllNBAPlayers()
+func GetNBATeamsWithPlayers(ctx context.Context, session *client.Client) (_ Teams, err error) {
+	ctx, span := tracing.Start(ctx, "static/nba.GetNBATeamsWithPlayers")
+	defer tracing.End(span, &err)
 
-	if players == nil || len(players) == 0 {
-		return nbaTeams
+	nbaTeams := GetNBATeams()
+	players, err := models.GetAllNBAPlayers(ctx, session)
+	if err != nil {
+		return nil, err
 	}
 	for _, player := range players {
 		if v, ok := weirdNameMap[player.Name]; ok {
@@ -144,17 +154,23 @@ func GetNBATeamsWithPlayers() Teams {
 
 	}
 
-	return nbaTeams
+	return nbaTeams, nil
 }
 
-func GetNBAMatchups() []Matchup {
-	gamesToday := models.GetNBAGamesToday()
-	if gamesToday == nil {
-		return nil
+func GetNBAMatchups(ctx context.Context, session *client.Client) (_ []Matchup, err error) {
+	ctx, span := tracing.Start(ctx, "static/nba.GetNBAMatchups")
+	defer tracing.End(span, &err)
+
+	gamesToday, err := models.GetNBAGamesToday(ctx, session)
+	if err != nil {
+		return nil, err
 	}
 
 	// Fetch the teams list once to prevent redundant calls
-	nbaTeams := GetNBATeamsWithPlayers()
+	nbaTeams, err := GetNBATeamsWithPlayers(ctx, session)
+	if err != nil {
+		return nil, err
+	}
 	var matchups []Matchup // Initialize slice to store matchups
 
 	for _, m := range gamesToday {
@@ -162,7 +178,7 @@ func GetNBAMatchups() []Matchup {
 		awayTeamID, err2 := m["VISITOR_TEAM_ID"].(json.Number).Int64()
 
 		if err1 != nil || err2 != nil {
-			fmt.Println("Error converting team IDs:", err1, err2)
+			logging.FromContext(ctx).WarnContext(ctx, "invalid team IDs in scoreboard", "error", errors.Join(err1, err2))
 			continue
 		}
 
@@ -170,7 +186,7 @@ func GetNBAMatchups() []Matchup {
 		awayTeam := nbaTeams.GetTeamByID(int(awayTeamID))
 
 		if homeTeam == nil || awayTeam == nil {
-			fmt.Printf("Warning: Could not find teams for match %d vs %d\n", homeTeamID, awayTeamID)
+			logging.FromContext(ctx).WarnContext(ctx, "could not find teams for matchup", "home_team_id", homeTeamID, "away_team_id", awayTeamID)
 			continue
 		}
 
@@ -180,7 +196,7 @@ func GetNBAMatchups() []Matchup {
 		})
 	}
 
-	return matchups
+	return matchups, nil
 }
 
 var weirdNameMap = map[string]string{
@@ -202,30 +218,44 @@ var weirdNameMap = map[string]string{
 }
 
 // GetNBAMatchupsWithOdds Get Today's Game and get acommpanying odds for the matchup
-func GetNBAMatchupsWithOdds() []Matchup {
-	gamesToday := models.GetNBAGamesToday()
-	matchOdds, err := odds.GetPlayerProps()
-	fullSeasonStats := models.GetAllNBAPlayerStatsFullSeason()
+func GetNBAMatchupsWithOdds(ctx context.Context, session *client.Client, oddsClient *oddsclient.Client) (_ []Matchup, err error) {
+	ctx, span := tracing.Start(ctx, "static/nba.GetNBAMatchupsWithOdds")
+	defer tracing.End(span, &err)
+
+	gamesToday, err := models.GetNBAGamesToday(ctx, session)
 	if err != nil {
-		return nil
+		return nil, err
 	}
-	if gamesToday == nil {
-		return nil
+	matchOdds, err := odds.GetPlayerProps(ctx, oddsClient)
+	if err != nil {
+		return nil, err
+	}
+	fullSeasonStats, err := models.GetAllNBAPlayerStatsFullSeason(ctx, session)
+	if err != nil {
+		return nil, err
 	}
 
 	// Fetch the teams list once to prevent redundant calls
-	nbaTeams := GetNBATeamsWithPlayers()
+	nbaTeams, err := GetNBATeamsWithPlayers(ctx, session)
+	if err != nil {
+		return nil, err
+	}
 	var matchups []Matchup // Initialize slice to store matchups
 
 	for _, m := range gamesToday {
-		fmt.Printf("%T", m["HOME_TEAM_ID"])
-		homeTeamID := int(m["HOME_TEAM_ID"].(float64))
-		awayTeamID := int(m["VISITOR_TEAM_ID"].(float64))
-		homeTeam := nbaTeams.GetTeamByID(homeTeamID)
-		awayTeam := nbaTeams.GetTeamByID(awayTeamID)
+		homeTeamID, err1 := m["HOME_TEAM_ID"].(json.Number).Int64()
+		awayTeamID, err2 := m["VISITOR_TEAM_ID"].(json.Number).Int64()
+
+		if err1 != nil || err2 != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "invalid team IDs in scoreboard", "error", errors.Join(err1, err2))
+			continue
+		}
+
+		homeTeam := nbaTeams.GetTeamByID(int(homeTeamID))
+		awayTeam := nbaTeams.GetTeamByID(int(awayTeamID))
 
 		if homeTeam == nil || awayTeam == nil {
-			fmt.Printf("Warning: Could not find teams for match %d vs %d\n", homeTeamID, awayTeamID)
+			logging.FromContext(ctx).WarnContext(ctx, "could not find teams for matchup", "home_team_id", homeTeamID, "away_team_id", awayTeamID)
 			continue
 		}
 
@@ -251,7 +281,7 @@ func GetNBAMatchupsWithOdds() []Matchup {
 						}
 
 					} else {
-						fmt.Println("No Player Found For", outcome.Description)
+						logging.FromContext(ctx).DebugContext(ctx, "no player found for prop", "player", outcome.Description)
 					}
 				}
 
@@ -264,15 +294,21 @@ func GetNBAMatchupsWithOdds() []Matchup {
 		})
 	}
 
-	return matchups
+	return matchups, nil
 }
 
-func GetActivePlayerForToday() []models.Player {
-	matchups := GetNBAMatchupsWithOdds()
+func GetActivePlayerForToday(ctx context.Context, session *client.Client, oddsClient *oddsclient.Client) (_ []models.Player, err error) {
+	ctx, span := tracing.Start(ctx, "static/nba.GetActivePlayerForToday")
+	defer tracing.End(span, &err)
+
+	matchups, err := GetNBAMatchupsWithOdds(ctx, session, oddsClient)
+	if err != nil {
+		return nil, err
+	}
 	var players []models.Player
 	for _, matchup := range matchups {
 		players = append(players, matchup.AwayTeam.Roster...)
 		players = append(players, matchup.HomeTeam.Roster...)
 	}
-	return players
+	return players, nil
 }
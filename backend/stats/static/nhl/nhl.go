@@ -1,9 +1,13 @@
 package nhl
 
 import (
-	"fmt"
+	"context"
+	client "sports_api/globals/nhl"
+	oddsclient "sports_api/globals/odds"
+	"sports_api/logging"
 	nhl2 "sports_api/odds/nhl"
 	"sports_api/stats/endpoints/nhl"
+	"sports_api/tracing"
 )
 
 type NHLMatchup struct {
@@ -11,25 +15,28 @@ type NHLMatchup struct {
 	Away *nhl.NHLTeam
 }
 
-func GetNHLMatchupsWithOdds() []NHLMatchup {
-	teams, err := nhl.GetAndParseNHLTeams()
+func GetNHLMatchupsWithOdds(ctx context.Context, session *client.Client, oddsClient *oddsclient.Client) (_ []NHLMatchup, err error) {
+	ctx, span := tracing.Start(ctx, "static/nhl.GetNHLMatchupsWithOdds")
+	defer tracing.End(span, &err)
+
+	teams, err := nhl.GetAndParseNHLTeams(ctx, session)
 	if err != nil {
-		return nil
+		return nil, err
 	}
-	props, err := nhl2.GetPlayerProps()
-	if err != nil || props == nil {
-		return nil
+	props, err := nhl2.GetPlayerProps(ctx, oddsClient)
+	if err != nil {
+		return nil, err
 	}
 	var nhlMatchups []NHLMatchup
 	for _, prop := range props {
 		home := teams.GetTeamByFullName(prop.HomeTeam)
 		if home == nil {
-			fmt.Println("Could not locate team", prop.HomeTeam)
+			logging.FromContext(ctx).WarnContext(ctx, "could not locate team", "team", prop.HomeTeam)
 			continue
 		}
 		away := teams.GetTeamByFullName(prop.AwayTeam)
 		if away == nil {
-			fmt.Println("Could not locate team", prop.AwayTeam)
+			logging.FromContext(ctx).WarnContext(ctx, "could not locate team", "team", prop.AwayTeam)
 			continue
 		}
 		for _, bookmaker := range prop.Bookmakers {
@@ -41,7 +48,7 @@ func GetNHLMatchupsWithOdds() []NHLMatchup {
 					} else if player := away.GetPlayerByFullName(outcome.Description); player != nil {
 						player.SetOutcome(bookmaker.Key, market.Key, outcome.Name, outcome.Point, outcome.Price)
 					} else {
-						fmt.Println("fail to find ", outcome.Description)
+						logging.FromContext(ctx).DebugContext(ctx, "no player found for prop", "player", outcome.Description)
 					}
 
 				}
@@ -53,5 +60,5 @@ func GetNHLMatchupsWithOdds() []NHLMatchup {
 			Away: away,
 		})
 	}
-	return nhlMatchups
+	return nhlMatchups, nil
 }